@@ -0,0 +1,17 @@
+data "cdcovhns_domain" "example" {
+  service_name = "example.com"
+}
+
+check "example_com_renewal" {
+  assert {
+    condition     = data.cdcovhns_domain.example.renew_mode == "automatic" && data.cdcovhns_domain.example.transfer_lock_status == "locked"
+    error_message = "example.com must renew automatically and be locked"
+  }
+}
+
+resource "cdcovhns_ds_records" "example" {
+  count = data.cdcovhns_domain.example.dnssec_supported ? 1 : 0
+
+  service_name = "example.com"
+  keys         = []
+}
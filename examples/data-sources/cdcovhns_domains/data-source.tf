@@ -0,0 +1,38 @@
+data "cdcovhns_domains" "external" {
+  tlds             = ["com", "fr"]
+  name_server_type = "external"
+}
+
+# The name servers of each domain are imported, as they cannot be created
+import {
+  for_each = toset(data.cdcovhns_domains.external.names)
+
+  to = cdcovhns_name_servers.all[each.key]
+  id = each.key
+}
+
+resource "cdcovhns_name_servers" "all" {
+  for_each = toset(data.cdcovhns_domains.external.names)
+
+  service_name = each.key
+
+  name_servers = [
+    {
+      host = "ns1.dns-provider.net"
+    },
+    {
+      host = "ns2.dns-provider.net"
+    },
+  ]
+}
+
+data "cdcovhns_domains" "expiring" {
+  expires_within_days = 30
+}
+
+check "no_expiring_domains" {
+  assert {
+    condition     = length(data.cdcovhns_domains.expiring.names) == 0
+    error_message = "Domains expiring within 30 days: ${join(", ", data.cdcovhns_domains.expiring.names)}"
+  }
+}
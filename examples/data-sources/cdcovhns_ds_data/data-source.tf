@@ -0,0 +1,17 @@
+data "cdcovhns_ds_data" "example" {
+  service_name = "example.com"
+  server       = "ns1.example.net"
+}
+
+resource "cdcovhns_ds_records" "example" {
+  service_name = "example.com"
+
+  keys = [
+    for key in data.cdcovhns_ds_data.example.keys : {
+      key_tag    = key.key_tag
+      algorithm  = key.algorithm
+      flags      = key.flags
+      public_key = key.public_key
+    } if key.flags == 257
+  ]
+}
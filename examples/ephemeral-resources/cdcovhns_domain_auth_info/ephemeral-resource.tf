@@ -0,0 +1,14 @@
+resource "cdcovhns_domain_transfer_lock" "example" {
+  service_name = "example.com"
+  status       = "unlocked"
+}
+
+ephemeral "cdcovhns_domain_auth_info" "example" {
+  service_name = cdcovhns_domain_transfer_lock.example.service_name
+}
+
+# Pass the auth code to the provider of the gaining registrar, eg: through
+# an ephemeral or write-only argument. It is not stored in state or plan files.
+locals {
+  auth_code = ephemeral.cdcovhns_domain_auth_info.example.auth_info
+}
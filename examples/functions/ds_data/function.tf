@@ -0,0 +1,3 @@
+output "ds_digest" {
+  value = provider::cdcovhns::ds_data("example.com", "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==")[0].digest_sha256
+}
@@ -0,0 +1 @@
+terraform import cdcovhns_domain_contacts.example example.com
@@ -0,0 +1,10 @@
+resource "cdcovhns_domain_contacts" "example" {
+  service_name = "example.com"
+  admin        = "ab12345-ovh"
+  tech         = "cd67890-ovh"
+  billing      = "ab12345-ovh"
+}
+
+output "owner" {
+  value = cdcovhns_domain_contacts.example.owner
+}
@@ -0,0 +1 @@
+terraform import cdcovhns_domain_renewal.example example.com
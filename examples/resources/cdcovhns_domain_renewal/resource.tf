@@ -0,0 +1,6 @@
+resource "cdcovhns_domain_renewal" "example" {
+  service_name            = "example.com"
+  renew_mode              = "automatic"
+  period                  = 12
+  expiration_warning_days = 60
+}
@@ -0,0 +1 @@
+terraform import cdcovhns_domain_transfer_lock.example example.com
@@ -0,0 +1,4 @@
+resource "cdcovhns_domain_transfer_lock" "example" {
+  service_name = "example.com"
+  status       = "locked"
+}
@@ -0,0 +1 @@
+terraform import cdcovhns_domain_whois_obfuscation.example example.com
@@ -0,0 +1,7 @@
+resource "cdcovhns_domain_whois_obfuscation" "example" {
+  service_name = "example.com"
+  email        = true
+  phone        = true
+  address      = true
+  name         = true
+}
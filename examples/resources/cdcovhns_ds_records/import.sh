@@ -0,0 +1 @@
+terraform import cdcovhns_ds_records.example example.com
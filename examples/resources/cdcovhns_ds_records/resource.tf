@@ -0,0 +1,12 @@
+resource "cdcovhns_ds_records" "example" {
+  service_name = "example.com"
+
+  keys = [
+    {
+      key_tag    = 2371
+      algorithm  = 13
+      flags      = 257
+      public_key = "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ=="
+    },
+  ]
+}
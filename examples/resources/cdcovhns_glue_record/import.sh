@@ -0,0 +1 @@
+terraform import cdcovhns_glue_record.ns1 example.com/ns1.example.com
@@ -0,0 +1,5 @@
+resource "cdcovhns_glue_record" "ns1" {
+  service_name = "example.com"
+  host         = "ns1.example.com"
+  ips          = ["192.0.2.53", "2001:db8::53"]
+}
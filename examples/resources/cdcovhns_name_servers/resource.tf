@@ -1,12 +1,12 @@
 resource "cdcovhns_name_servers" "examplecom" {
   service_name = "example.com"
 
-  name_servers = {
-    "ns1" = {
+  name_servers = [
+    {
       host = "noel.ns.example.com"
     },
-    "ns2" = {
+    {
       host = "june.ns.example.com"
     },
-  }
+  ]
 }
@@ -1,8 +1,9 @@
 package api
 
 import (
+	"context"
 	"fmt"
-	"strconv"
+	"net/url"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -10,12 +11,12 @@ import (
 
 const CHECK_STATUS_WAIT_TIME time.Duration = 15 * time.Second
 
-func (c APIClient) DeleteNameServers(serviceName string) error {
+func (c APIClient) DeleteNameServers(serviceName DomainName) error {
 	return c.SetNameServerType(serviceName, NSHosted)
 }
 
-func (c APIClient) UpdateNameServers(serviceName string, data *NameServerUpdateRequest) (NameServerTask, error) {
-	endpoint := fmt.Sprintf("/domain/%s/nameServers/update", serviceName)
+func (c APIClient) UpdateNameServers(serviceName DomainName, data *NameServerUpdateRequest) (NameServerTask, error) {
+	endpoint := fmt.Sprintf("/domain/%s/nameServers/update", serviceName.PathEscaped())
 	response := NameServerTask{}
 
 	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateNameServers ENDPOINT: %s", endpoint))
@@ -30,8 +31,8 @@ func (c APIClient) UpdateNameServers(serviceName string, data *NameServerUpdateR
 	return response, err
 }
 
-func (c APIClient) GetNameServersType(serviceName string) (NameServerType, error) {
-	endpoint := fmt.Sprintf("/domain/%s", serviceName)
+func (c APIClient) GetNameServersType(serviceName DomainName) (NameServerType, error) {
+	endpoint := fmt.Sprintf("/domain/%s", serviceName.PathEscaped())
 	response := NameServerType{}
 
 	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetNameServersType ENDPOINT: %s", endpoint))
@@ -45,7 +46,7 @@ func (c APIClient) GetNameServersType(serviceName string) (NameServerType, error
 	return response, err
 }
 
-func (c APIClient) SetNameServerType(serviceName string, nsType string) error {
+func (c APIClient) SetNameServerType(serviceName DomainName, nsType string) error {
 	var nsTypeObject NameServerType
 
 	if nsType == NSExternal {
@@ -56,7 +57,7 @@ func (c APIClient) SetNameServerType(serviceName string, nsType string) error {
 		return fmt.Errorf("wrong name server type. Use hosted or external")
 	}
 
-	endpoint := fmt.Sprintf("/domain/%s", serviceName)
+	endpoint := fmt.Sprintf("/domain/%s", serviceName.PathEscaped())
 	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] SetNameServerType ENDPOINT: %s", endpoint))
 	err := c.Client.Put(
 		endpoint,
@@ -67,20 +68,22 @@ func (c APIClient) SetNameServerType(serviceName string, nsType string) error {
 	return err
 }
 
-func (c APIClient) CheckOVHTask(err chan<- error, domain string, id int64) {
-	endpoint := fmt.Sprintf("/domain/%s/task/%d", domain, id)
+func (c APIClient) CheckOVHTask(ctx context.Context, err chan<- error, domain DomainName, id int64) {
+	endpoint := fmt.Sprintf("/domain/%s/task/%d", domain.PathEscaped(), id)
 
 	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CheckOVHTask ENDPOINT: %s", endpoint))
 
 	apiErr := error(nil)
 	for {
-		time.Sleep(CHECK_STATUS_WAIT_TIME)
+		select {
+		case <-ctx.Done():
+			tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CheckOVHTask stopped: %v", ctx.Err()))
+			err <- fmt.Errorf("stopped waiting for task %d: %w", id, ctx.Err())
+			return
+		case <-time.After(CHECK_STATUS_WAIT_TIME):
+		}
 
-		response := TaskReposnse{}
-		err := c.Client.Get(
-			endpoint,
-			&response,
-		)
+		response, err := c.GetOVHTask(domain, id)
 
 		tflog.Debug(c.ctx, "[CDC_OVH] CheckOVHTask after get call")
 
@@ -115,11 +118,26 @@ func (c APIClient) CheckOVHTask(err chan<- error, domain string, id int64) {
 	err <- apiErr
 }
 
-func (c APIClient) GetNameServersFromAPI(serviceName string) (map[string]NameServerOvhResponse, error) {
+func (c APIClient) GetOVHTask(domain DomainName, id int64) (DomainTask, error) {
+	endpoint := fmt.Sprintf("/domain/%s/task/%d", domain.PathEscaped(), id)
+	response := DomainTask{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetOVHTask ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetOVHTask RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetOVHTask ERR: %v", err))
+
+	return response, err
+}
+
+func (c APIClient) GetNameServersFromAPI(serviceName DomainName) ([]NameServerOvhResponse, error) {
 	var ids []uint64
-	nameServers := make(map[string]NameServerOvhResponse)
+	nameServers := []NameServerOvhResponse{}
 
-	endpoint := fmt.Sprintf("/domain/%s/nameServer", serviceName)
+	endpoint := fmt.Sprintf("/domain/%s/nameServer", serviceName.PathEscaped())
 	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetNameServersFromAPI ENDPOINT: %v", endpoint))
 	err := c.Client.Get(
 		endpoint,
@@ -133,10 +151,10 @@ func (c APIClient) GetNameServersFromAPI(serviceName string) (map[string]NameSer
 		return nil, err
 	}
 
-	for key, id := range ids {
+	for _, id := range ids {
 		// Get NS data
 		nsResponse := NameServerOvhResponse{}
-		nsDataEndpoint := fmt.Sprintf("/domain/%s/nameServer/%v", serviceName, id)
+		nsDataEndpoint := fmt.Sprintf("/domain/%s/nameServer/%v", serviceName.PathEscaped(), id)
 		tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetNameServersFromAPI loop ENDPOINT: %v", nsDataEndpoint))
 
 		err := c.Client.Get(
@@ -151,48 +169,56 @@ func (c APIClient) GetNameServersFromAPI(serviceName string) (map[string]NameSer
 			return nil, err
 		}
 
-		nameServers["ns"+strconv.Itoa(key+1)] = nsResponse
+		nameServers = append(nameServers, nsResponse)
 	}
 
 	return nameServers, nil
 }
 
-func (c APIClient) CheckCurrentTaskState(serviceName string) error {
-	var ids []uint64
+func (c APIClient) GetPendingTasks(serviceName DomainName, status string) ([]int64, error) {
+	var ids []int64
 
-	checkDoingTasksEndpoint := fmt.Sprintf("/domain/%s/task?status=%s", serviceName, "doing")
-	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CheckCurrentTaskState doing ENDPOINT: %v", checkDoingTasksEndpoint))
+	endpoint := fmt.Sprintf("/domain/%s/task?status=%s", serviceName.PathEscaped(), url.QueryEscape(status))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetPendingTasks %s ENDPOINT: %v", status, endpoint))
 
-	doingErr := c.Client.Get(
-		checkDoingTasksEndpoint,
+	err := c.Client.Get(
+		endpoint,
 		&ids,
 	)
 
-	if doingErr != nil {
-		tflog.Debug(c.ctx, fmt.Sprintf("[CDC] CheckCurrentTaskState doing ERR: %v", doingErr))
-		return doingErr
+	if err != nil {
+		tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetPendingTasks %s ERR: %v", status, err))
+		return nil, err
 	}
-	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CheckCurrentTaskState doing RESP: %v", ids))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetPendingTasks %s RESP: %v", status, ids))
 
-	if len(ids) > 0 {
-		return fmt.Errorf("some tasks are already in doing state for domain %s", serviceName)
-	}
+	return ids, nil
+}
 
-	checkTodoTasksEndpoint := fmt.Sprintf("/domain/%s/task?status=%s", serviceName, "todo")
-	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CheckCurrentTaskState todo ENDPOINT: %v", checkTodoTasksEndpoint))
-	todoErr := c.Client.Get(
-		checkTodoTasksEndpoint,
-		&ids,
-	)
-	if todoErr != nil {
-		tflog.Debug(c.ctx, fmt.Sprintf("[CDC] CheckCurrentTaskState todo ERR: %v", todoErr))
-		return todoErr
-	}
-	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CheckCurrentTaskState todo RESP: %v", ids))
+// CheckCurrentTaskState returns an error when tasks other than ownTaskIDs are
+// in todo or doing state for the domain.
+func (c APIClient) CheckCurrentTaskState(serviceName DomainName, ownTaskIDs ...int64) error {
+	for _, status := range []string{"doing", "todo"} {
+		ids, err := c.GetPendingTasks(serviceName, status)
+		if err != nil {
+			return err
+		}
 
-	if len(ids) > 0 {
-		return fmt.Errorf("some tasks are already in todo state for domain %s", serviceName)
+		for _, id := range ids {
+			if !containsTaskID(ownTaskIDs, id) {
+				return fmt.Errorf("some tasks are already in %s state for domain %s", status, serviceName)
+			}
+		}
 	}
 
 	return nil
 }
+
+func containsTaskID(ids []int64, id int64) bool {
+	for _, ownID := range ids {
+		if ownID == id {
+			return true
+		}
+	}
+	return false
+}
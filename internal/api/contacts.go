@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ChangeContact starts the change of the admin, billing or tech contacts of
+// serviceName and returns the ids of the contact change tasks.
+func (c APIClient) ChangeContact(serviceName DomainName, contacts ContactChangeRequest) ([]int64, error) {
+	endpoint := fmt.Sprintf("/domain/%s/changeContact", serviceName.PathEscaped())
+	var ids []int64
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ChangeContact ENDPOINT: %s", endpoint))
+	err := c.Client.Post(
+		endpoint,
+		&contacts,
+		&ids,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ChangeContact RESP: %v", ids))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ChangeContact ERR: %v", err))
+
+	return ids, err
+}
+
+func (c APIClient) GetContactChangeTask(id int64) (ContactChangeTask, error) {
+	endpoint := fmt.Sprintf("/me/task/contactChange/%d", id)
+	response := ContactChangeTask{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetContactChangeTask ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetContactChangeTask RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetContactChangeTask ERR: %v", err))
+
+	return response, err
+}
+
+// PendingContactChangeTasks returns the contact change tasks of serviceName
+// that are not finished yet. The account tasks are listed per pending state,
+// as the API cannot filter them by domain, so finished ones are never read.
+func (c APIClient) PendingContactChangeTasks(serviceName DomainName) ([]ContactChangeTask, error) {
+	pending := []ContactChangeTask{}
+
+	for _, state := range []string{ContactChangeTodo, ContactChangeDoing, ContactChangeCheckValidity, ContactChangeValidatingByCustomers} {
+		var ids []int64
+
+		endpoint := "/me/task/contactChange?state=" + url.QueryEscape(state)
+		tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] PendingContactChangeTasks ENDPOINT: %s", endpoint))
+		err := c.Client.Get(
+			endpoint,
+			&ids,
+		)
+		tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] PendingContactChangeTasks RESP: %v", ids))
+
+		if err != nil {
+			tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] PendingContactChangeTasks ERR: %v", err))
+			return nil, err
+		}
+
+		for _, id := range ids {
+			task, err := c.GetContactChangeTask(id)
+			if err != nil {
+				return nil, err
+			}
+
+			if strings.EqualFold(task.Product.Name, serviceName.String()) {
+				pending = append(pending, task)
+			}
+		}
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ovh/go-ovh/ovh"
+)
+
+func TestPendingContactChangeTasks(t *testing.T) {
+	idsByState := map[string][]int64{
+		ContactChangeTodo:                  {1},
+		ContactChangeDoing:                 {2, 3},
+		ContactChangeCheckValidity:         {},
+		ContactChangeValidatingByCustomers: {4},
+		ContactChangeDone:                  {5},
+	}
+	products := map[string]string{
+		"1": "example.com",
+		"2": "Example.COM",
+		"3": "example.net",
+		"4": "example.com",
+		"5": "example.com",
+	}
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/me/task/contactChange" {
+			ids, ok := idsByState[r.URL.Query().Get("state")]
+			if !ok {
+				http.Error(w, `{"message":"unknown state"}`, http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(ids)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/me/task/contactChange/")
+		taskID, _ := strconv.ParseInt(id, 10, 64)
+		json.NewEncoder(w).Encode(ContactChangeTask{ID: taskID, Product: ContactChangeTaskProduct{Name: products[id]}})
+	}))
+
+	testCases := map[string]struct {
+		serviceName DomainName
+		wantNames   []string
+	}{
+		"pending tasks of the domain": {serviceName: "example.com", wantNames: []string{"example.com", "Example.COM", "example.com"}},
+		"other domain":                {serviceName: "example.net", wantNames: []string{"example.net"}},
+		"no task":                     {serviceName: "example.org", wantNames: []string{}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tasks, err := client.PendingContactChangeTasks(testCase.serviceName)
+			if err != nil {
+				t.Fatalf("PendingContactChangeTasks returned %q", err)
+			}
+
+			names := []string{}
+			for _, task := range tasks {
+				names = append(names, task.Product.Name)
+			}
+
+			if !reflect.DeepEqual(names, testCase.wantNames) {
+				t.Errorf("PendingContactChangeTasks(%q) products = %q, want %q", testCase.serviceName, names, testCase.wantNames)
+			}
+		})
+	}
+}
+
+func TestPendingContactChangeTasksError(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"This call has not been granted"}`, http.StatusForbidden)
+	}))
+
+	if _, err := client.PendingContactChangeTasks("example.com"); err == nil {
+		t.Errorf("PendingContactChangeTasks returned no error")
+	}
+}
+
+// newTestClient returns a client of an OVH API served by handler until the
+// test ends. The server time needed to sign requests is answered here.
+func newTestClient(t *testing.T, handler http.Handler) APIClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth/time" {
+			fmt.Fprint(w, time.Now().Unix())
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := ovh.NewClient(server.URL, "application-key", "application-secret", "consumer-key")
+	if err != nil {
+		t.Fatalf("could not create the OVH client: %s", err)
+	}
+
+	return APIClient{Client: client, ctx: context.Background()}
+}
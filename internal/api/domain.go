@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/ovh/go-ovh/ovh"
+	"golang.org/x/net/idna"
+)
+
+var domainLabelRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// DomainName is a domain name in the form used by the OVH API: lowercase,
+// punycode, without trailing dot. Build it with ParseDomainName.
+type DomainName string
+
+// ParseDomainName validates name and returns its canonical form.
+func ParseDomainName(name string) (DomainName, error) {
+	canonical, err := parseName(name, "domain name")
+	return DomainName(canonical), err
+}
+
+// ParseHostname validates a fully qualified host name against RFC 1123 once
+// converted to punycode, and returns it lowercase, in punycode and without
+// trailing dot.
+func ParseHostname(name string) (string, error) {
+	return parseName(name, "host name")
+}
+
+// parseName implements ParseDomainName and ParseHostname, kind names the value
+// in errors.
+func parseName(name string, kind string) (string, error) {
+	canonical := strings.ToLower(strings.TrimSuffix(name, "."))
+
+	if canonical == "" {
+		return "", fmt.Errorf("%s is empty", kind)
+	}
+
+	canonical, err := idna.Lookup.ToASCII(canonical)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a valid internationalised %s: %s", name, kind, err.Error())
+	}
+
+	if len(canonical) > 253 {
+		return "", fmt.Errorf("%q is longer than 253 characters", name)
+	}
+
+	labels := strings.Split(canonical, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("%q is not a fully qualified %s", name, kind)
+	}
+
+	for _, label := range labels {
+		if !domainLabelRegexp.MatchString(label) {
+			return "", fmt.Errorf("%q has an invalid label %q: use 1 to 63 letters, digits or hyphens, not starting or ending with a hyphen", name, label)
+		}
+	}
+
+	return canonical, nil
+}
+
+func (d DomainName) String() string {
+	return string(d)
+}
+
+// PathEscaped returns the name escaped for use in an endpoint path.
+func (d DomainName) PathEscaped() string {
+	return url.PathEscape(string(d))
+}
+
+// ListDomains returns the domains of the account, only those owned by the
+// whoisOwner contact when it is not empty.
+func (c APIClient) ListDomains(whoisOwner string) ([]DomainName, error) {
+	var domains []DomainName
+
+	endpoint := "/domain"
+	if whoisOwner != "" {
+		endpoint += "?whoisOwner=" + url.QueryEscape(whoisOwner)
+	}
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ListDomains ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&domains,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ListDomains RESP: %v", domains))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ListDomains ERR: %v", err))
+
+	return domains, err
+}
+
+// DomainExists reports whether serviceName is a domain of the account.
+func (c APIClient) DomainExists(serviceName DomainName) (bool, error) {
+	domains, err := c.ListDomains("")
+	if err != nil {
+		return false, err
+	}
+
+	for _, domain := range domains {
+		if strings.EqualFold(string(domain), string(serviceName)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (c APIClient) GetDomain(serviceName DomainName) (Domain, error) {
+	endpoint := fmt.Sprintf("/domain/%s", serviceName.PathEscaped())
+	response := Domain{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDomain ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDomain RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDomain ERR: %v", err))
+
+	return response, err
+}
+
+// SetTransferLockStatus asks the registry to lock or unlock the transfer of
+// serviceName. The domain goes through locking or unlocking first.
+func (c APIClient) SetTransferLockStatus(serviceName DomainName, status string) error {
+	if status != TransferLockLocked && status != TransferLockUnlocked {
+		return fmt.Errorf("wrong transfer lock status. Use locked or unlocked")
+	}
+
+	endpoint := fmt.Sprintf("/domain/%s", serviceName.PathEscaped())
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] SetTransferLockStatus ENDPOINT: %s", endpoint))
+	err := c.Client.Put(
+		endpoint,
+		TransferLockStatus{TransferLockStatus: status},
+		nil,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] SetTransferLockStatus ERR: %v", err))
+	return err
+}
+
+// GetAuthInfo returns the auth code needed to transfer serviceName to another
+// registrar.
+func (c APIClient) GetAuthInfo(serviceName DomainName) (string, error) {
+	endpoint := fmt.Sprintf("/domain/%s/authInfo", serviceName.PathEscaped())
+	var response string
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetAuthInfo ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetAuthInfo ERR: %v", err))
+
+	return response, err
+}
+
+// IsNotFound reports whether err is an OVH API 404, eg: the domain expired or
+// was transferred out of the account.
+func IsNotFound(err error) bool {
+	var apiErr *ovh.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusNotFound
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+func (c APIClient) GetDSRecords(serviceName DomainName) ([]DSRecord, error) {
+	var ids []int64
+	dsRecords := []DSRecord{}
+
+	endpoint := fmt.Sprintf("/domain/%s/dsRecord", serviceName.PathEscaped())
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDSRecords ENDPOINT: %v", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&ids,
+	)
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDSRecords RESP: %v", ids))
+
+	if err != nil {
+		tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDSRecords ERR: %v", err))
+		return nil, err
+	}
+
+	for _, id := range ids {
+		dsRecord := DSRecord{}
+		dsDataEndpoint := fmt.Sprintf("/domain/%s/dsRecord/%v", serviceName.PathEscaped(), id)
+		tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDSRecords loop ENDPOINT: %v", dsDataEndpoint))
+
+		err := c.Client.Get(
+			dsDataEndpoint,
+			&dsRecord,
+		)
+
+		tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDSRecords RESP: %v", dsRecord))
+
+		if err != nil {
+			tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetDSRecords ERR: %v", err))
+			return nil, err
+		}
+
+		dsRecords = append(dsRecords, dsRecord)
+	}
+
+	return dsRecords, nil
+}
+
+func (c APIClient) UpdateDSRecords(serviceName DomainName, keys []DSRecordKey) (DomainTask, error) {
+	endpoint := fmt.Sprintf("/domain/%s/dsRecord/update", serviceName.PathEscaped())
+	response := DomainTask{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateDSRecords ENDPOINT: %s", endpoint))
+	err := c.Client.Post(
+		endpoint,
+		&DSRecordUpdateRequest{Keys: keys},
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateDSRecords RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateDSRecords ERR: %v", err))
+
+	return response, err
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+func (c APIClient) GetGlueRecord(serviceName DomainName, host string) (GlueRecord, error) {
+	endpoint := fmt.Sprintf("/domain/%s/glueRecord/%s", serviceName.PathEscaped(), url.PathEscape(host))
+	response := GlueRecord{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetGlueRecord ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetGlueRecord RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetGlueRecord ERR: %v", err))
+
+	return response, err
+}
+
+func (c APIClient) CreateGlueRecord(serviceName DomainName, data *GlueRecord) (DomainTask, error) {
+	endpoint := fmt.Sprintf("/domain/%s/glueRecord", serviceName.PathEscaped())
+	response := DomainTask{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CreateGlueRecord ENDPOINT: %s", endpoint))
+	err := c.Client.Post(
+		endpoint,
+		data,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CreateGlueRecord RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] CreateGlueRecord ERR: %v", err))
+
+	return response, err
+}
+
+func (c APIClient) UpdateGlueRecord(serviceName DomainName, host string, ips []string) (DomainTask, error) {
+	endpoint := fmt.Sprintf("/domain/%s/glueRecord/%s/update", serviceName.PathEscaped(), url.PathEscape(host))
+	response := DomainTask{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateGlueRecord ENDPOINT: %s", endpoint))
+	err := c.Client.Post(
+		endpoint,
+		&GlueRecordUpdateRequest{IPs: ips},
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateGlueRecord RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateGlueRecord ERR: %v", err))
+
+	return response, err
+}
+
+func (c APIClient) DeleteGlueRecord(serviceName DomainName, host string) (DomainTask, error) {
+	endpoint := fmt.Sprintf("/domain/%s/glueRecord/%s", serviceName.PathEscaped(), url.PathEscape(host))
+	response := DomainTask{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] DeleteGlueRecord ENDPOINT: %s", endpoint))
+	err := c.Client.Delete(
+		endpoint,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] DeleteGlueRecord RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] DeleteGlueRecord ERR: %v", err))
+
+	return response, err
+}
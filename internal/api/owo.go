@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// GetObfuscatedFields returns the WHOIS fields of serviceName hidden by OVH.
+func (c APIClient) GetObfuscatedFields(serviceName DomainName) ([]string, error) {
+	endpoint := fmt.Sprintf("/domain/%s/owo", serviceName.PathEscaped())
+	fields := []string{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetObfuscatedFields ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&fields,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetObfuscatedFields RESP: %v", fields))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetObfuscatedFields ERR: %v", err))
+
+	return fields, err
+}
+
+// ObfuscateFields hides fields from the WHOIS of serviceName.
+func (c APIClient) ObfuscateFields(serviceName DomainName, fields []string) error {
+	endpoint := fmt.Sprintf("/domain/%s/owo", serviceName.PathEscaped())
+	var response []string
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ObfuscateFields ENDPOINT: %s", endpoint))
+	err := c.Client.Post(
+		endpoint,
+		&OwoRequest{Fields: fields},
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ObfuscateFields RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] ObfuscateFields ERR: %v", err))
+
+	return err
+}
+
+// UnobfuscateField shows field in the WHOIS of serviceName again.
+func (c APIClient) UnobfuscateField(serviceName DomainName, field string) error {
+	endpoint := fmt.Sprintf("/domain/%s/owo/%s", serviceName.PathEscaped(), url.PathEscape(field))
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UnobfuscateField ENDPOINT: %s", endpoint))
+	err := c.Client.Delete(
+		endpoint,
+		nil,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UnobfuscateField ERR: %v", err))
+
+	return err
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+func (c APIClient) GetServiceInfos(serviceName DomainName) (ServiceInfos, error) {
+	endpoint := fmt.Sprintf("/domain/%s/serviceInfos", serviceName.PathEscaped())
+	response := ServiceInfos{}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetServiceInfos ENDPOINT: %s", endpoint))
+	err := c.Client.Get(
+		endpoint,
+		&response,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetServiceInfos RESP: %v", response))
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] GetServiceInfos ERR: %v", err))
+
+	return response, err
+}
+
+// UpdateServiceRenew replaces the renewal settings of serviceName.
+func (c APIClient) UpdateServiceRenew(serviceName DomainName, renew ServiceRenew) error {
+	endpoint := fmt.Sprintf("/domain/%s/serviceInfos", serviceName.PathEscaped())
+
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateServiceRenew ENDPOINT: %s", endpoint))
+	err := c.Client.Put(
+		endpoint,
+		&ServiceInfosUpdateRequest{Renew: renew},
+		nil,
+	)
+	tflog.Debug(c.ctx, fmt.Sprintf("[CDC_OVH] UpdateServiceRenew ERR: %v", err))
+
+	return err
+}
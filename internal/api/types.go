@@ -9,6 +9,10 @@ type NameServerType struct {
 	NameServerType string `json:"nameServerType"`
 }
 
+type TransferLockStatus struct {
+	TransferLockStatus string `json:"transferLockStatus"`
+}
+
 type NameServerUpdateRequest struct {
 	NameServers []*NameServerCreatePayload `json:"nameServers"`
 }
@@ -18,8 +22,15 @@ type NameServerCreatePayload struct {
 	IP   string `json:"ip,omitempty"`
 }
 
-type TaskReposnse struct {
-	Status string `json:"status"`
+type DomainTask struct {
+	ID           int64  `json:"id"`
+	Function     string `json:"function"`
+	Status       string `json:"status"`
+	Comment      string `json:"comment,omitempty"`
+	CreationDate string `json:"creationDate"`
+	TodoDate     string `json:"todoDate,omitempty"`
+	LastUpdate   string `json:"lastUpdate,omitempty"`
+	DoneDate     string `json:"doneDate,omitempty"`
 }
 
 type NameServerOvhResponse struct {
@@ -45,6 +56,165 @@ func (n *NameServerOvhResponse) GetIP() string {
 }
 
 type NameServerTask struct {
-	ID          int64  `json:"id"`
-	ServiceName string `json:"domain"`
+	ID          int64      `json:"id"`
+	ServiceName DomainName `json:"domain"`
+}
+
+const (
+	DSRecordDeleting string = "deleting"
+	DSRecordDeleted  string = "deleted"
+)
+
+// DSRecord is a DNSSEC key published at the registry. OVH computes the DS
+// digest from the public key.
+type DSRecord struct {
+	ID         int64  `json:"id"`
+	Algorithm  int    `json:"algorithm"`
+	Flags      int    `json:"flags"`
+	KeyTag     int    `json:"keyTag"`
+	PublicKey  string `json:"publicKey"`
+	Status     string `json:"status,omitempty"`
+	LastUpdate string `json:"lastUpdate,omitempty"`
+}
+
+// DSRecordKey is a key sent to replace the DS records of a domain. The API
+// names the key tag "tag" here and "keyTag" when reading.
+type DSRecordKey struct {
+	Algorithm int    `json:"algorithm"`
+	Flags     int    `json:"flags"`
+	PublicKey string `json:"publicKey"`
+	Tag       int    `json:"tag"`
+}
+
+type DSRecordUpdateRequest struct {
+	Keys []DSRecordKey `json:"keys"`
+}
+
+const (
+	DomainStateOK            string = "ok"
+	DomainStateExpired       string = "expired"
+	DomainStatePendingDelete string = "pendingDelete"
+	DomainStateRestorable    string = "restorable"
+	DomainStateDispute       string = "dispute"
+
+	DomainSuspended string = "suspended"
+
+	TransferLockLocked      string = "locked"
+	TransferLockLocking     string = "locking"
+	TransferLockUnlocked    string = "unlocked"
+	TransferLockUnlocking   string = "unlocking"
+	TransferLockUnavailable string = "unavailable"
+)
+
+type Domain struct {
+	Domain                     string               `json:"domain"`
+	NameServerType             string               `json:"nameServerType"`
+	TransferLockStatus         string               `json:"transferLockStatus"`
+	State                      string               `json:"state,omitempty"`
+	SuspensionState            string               `json:"suspensionState,omitempty"`
+	Expiration                 string               `json:"expiration,omitempty"`
+	LastUpdate                 string               `json:"lastUpdate,omitempty"`
+	Offer                      string               `json:"offer,omitempty"`
+	WhoisOwner                 string               `json:"whoisOwner,omitempty"`
+	OwoSupported               bool                 `json:"owoSupported"`
+	DNSSECSupported            bool                 `json:"dnssecSupported"`
+	GlueRecordIPv6Supported    bool                 `json:"glueRecordIpv6Supported"`
+	GlueRecordMultiIPSupported bool                 `json:"glueRecordMultiIpSupported"`
+	HostSupported              bool                 `json:"hostSupported"`
+	ParentService              *DomainParentService `json:"parentService,omitempty"`
+}
+
+type DomainParentService struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type GlueRecord struct {
+	Host string   `json:"host"`
+	IPs  []string `json:"ips"`
+}
+
+type GlueRecordUpdateRequest struct {
+	IPs []string `json:"ips"`
+}
+
+// ServiceInfos is the billing side of a domain, dates use the 2006-01-02
+// layout.
+type ServiceInfos struct {
+	Domain                string       `json:"domain"`
+	ServiceID             int64        `json:"serviceId"`
+	Status                string       `json:"status"`
+	Creation              string       `json:"creation"`
+	Expiration            string       `json:"expiration"`
+	EngagedUpTo           string       `json:"engagedUpTo,omitempty"`
+	RenewalType           string       `json:"renewalType"`
+	CanDeleteAtExpiration bool         `json:"canDeleteAtExpiration"`
+	PossibleRenewPeriod   []int64      `json:"possibleRenewPeriod"`
+	Renew                 ServiceRenew `json:"renew"`
+	ContactAdmin          string       `json:"contactAdmin"`
+	ContactBilling        string       `json:"contactBilling"`
+	ContactTech           string       `json:"contactTech"`
+}
+
+type ServiceRenew struct {
+	Automatic          bool   `json:"automatic"`
+	DeleteAtExpiration bool   `json:"deleteAtExpiration"`
+	Forced             bool   `json:"forced"`
+	ManualPayment      bool   `json:"manualPayment"`
+	Period             *int64 `json:"period"`
+}
+
+type ServiceInfosUpdateRequest struct {
+	Renew ServiceRenew `json:"renew"`
+}
+
+// ContactChangeRequest changes the contacts that are set, by NIC handle.
+type ContactChangeRequest struct {
+	ContactAdmin   string `json:"contactAdmin,omitempty"`
+	ContactBilling string `json:"contactBilling,omitempty"`
+	ContactTech    string `json:"contactTech,omitempty"`
+}
+
+const (
+	ContactChangeAborted               string = "aborted"
+	ContactChangeCheckValidity         string = "checkValidity"
+	ContactChangeDoing                 string = "doing"
+	ContactChangeDone                  string = "done"
+	ContactChangeError                 string = "error"
+	ContactChangeExpired               string = "expired"
+	ContactChangeRefused               string = "refused"
+	ContactChangeTodo                  string = "todo"
+	ContactChangeValidatingByCustomers string = "validatingByCustomers"
+)
+
+// ContactChangeTask is an account task, not a domain task. Contacts may have
+// to validate it by email.
+type ContactChangeTask struct {
+	ID            int64                    `json:"id"`
+	State         string                   `json:"state"`
+	FromAccount   string                   `json:"fromAccount"`
+	ToAccount     string                   `json:"toAccount"`
+	AskingAccount string                   `json:"askingAccount"`
+	ContactTypes  []string                 `json:"contactTypes"`
+	DateRequest   string                   `json:"dateRequest"`
+	DateDone      string                   `json:"dateDone,omitempty"`
+	LastUpdate    string                   `json:"lastUpdate,omitempty"`
+	Product       ContactChangeTaskProduct `json:"product"`
+}
+
+type ContactChangeTaskProduct struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// WHOIS fields that OVH can hide (OWO, OVH Whois Obfuscator).
+const (
+	OwoFieldAddress string = "address"
+	OwoFieldEmail   string = "email"
+	OwoFieldName    string = "name"
+	OwoFieldPhone   string = "phone"
+)
+
+type OwoRequest struct {
+	Fields []string `json:"fields"`
 }
@@ -0,0 +1,467 @@
+package dnsclient
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/miekg/dns"
+	"golang.org/x/net/idna"
+)
+
+const (
+	DefaultPort    int           = 53
+	DefaultTimeout time.Duration = 5 * time.Second
+
+	resolvConfPath string = "/etc/resolv.conf"
+)
+
+type Config struct {
+	// Resolver is the recursive resolver (host or host:port) used to look up
+	// name server addresses. Empty means the system resolver.
+	Resolver string
+	// Port is used when querying name servers directly.
+	Port    int
+	Timeout time.Duration
+}
+
+type Client struct {
+	resolver string
+	port     string
+	timeout  time.Duration
+}
+
+func NewClient(cfg Config) (*Client, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	if port < 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid DNS port %d", port)
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	resolver := cfg.Resolver
+	if resolver == "" {
+		// Missing system configuration only matters once a lookup is made.
+		if conf, err := dns.ClientConfigFromFile(resolvConfPath); err == nil && len(conf.Servers) > 0 {
+			resolver = net.JoinHostPort(conf.Servers[0], conf.Port)
+		}
+	} else if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(resolver, strconv.Itoa(DefaultPort))
+	}
+
+	return &Client{
+		resolver: resolver,
+		port:     strconv.Itoa(port),
+		timeout:  timeout,
+	}, nil
+}
+
+// Exchange sends a single question to server, retrying over TCP when the UDP
+// answer is truncated.
+func (c *Client) Exchange(ctx context.Context, server string, name string, qtype uint16, recursive bool) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(CanonicalHost(name)), qtype)
+	msg.RecursionDesired = recursive
+	msg.SetEdns0(dns.DefaultMsgSize, false)
+
+	client := &dns.Client{Timeout: c.timeout}
+
+	tflog.Debug(ctx, fmt.Sprintf("[CDC_DNS] Exchange %s %s @%s", name, dns.TypeToString[qtype], server))
+	resp, _, err := client.ExchangeContext(ctx, msg, server)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, server)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("[CDC_DNS] Exchange RESP: %v", resp))
+	tflog.Debug(ctx, fmt.Sprintf("[CDC_DNS] Exchange ERR: %v", err))
+
+	if err != nil {
+		return nil, fmt.Errorf("querying %s for %s %s: %w", server, name, dns.TypeToString[qtype], err)
+	}
+
+	return resp, nil
+}
+
+// Resolve asks the configured recursive resolver and returns the answer
+// records of the requested type.
+func (c *Client) Resolve(ctx context.Context, name string, qtype uint16) ([]dns.RR, error) {
+	if c.resolver == "" {
+		return nil, fmt.Errorf("no DNS resolver configured and none found in %s", resolvConfPath)
+	}
+
+	resp, err := c.Exchange(ctx, c.resolver, name, qtype, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("resolving %s %s: %s", name, dns.TypeToString[qtype], dns.RcodeToString[resp.Rcode])
+	}
+
+	var records []dns.RR
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == qtype {
+			records = append(records, rr)
+		}
+	}
+
+	return records, nil
+}
+
+// LookupHost returns the IPv4 and IPv6 addresses of host.
+func (c *Client) LookupHost(ctx context.Context, host string) ([]string, error) {
+	var addresses []string
+
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		records, err := c.Resolve(ctx, host, qtype)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rr := range records {
+			switch record := rr.(type) {
+			case *dns.A:
+				addresses = append(addresses, record.A.String())
+			case *dns.AAAA:
+				addresses = append(addresses, record.AAAA.String())
+			}
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("host %s has no A or AAAA records", host)
+	}
+
+	return addresses, nil
+}
+
+// ServerAddresses returns the host:port pairs used to query a name server
+// directly. The glue ip is preferred when given, otherwise the host is looked
+// up through the resolver.
+func (c *Client) ServerAddresses(ctx context.Context, host string, ip string) ([]string, error) {
+	ips := []string{}
+	if ip != "" {
+		ips = append(ips, ip)
+	} else if net.ParseIP(host) != nil {
+		ips = append(ips, host)
+	} else {
+		resolved, err := c.LookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		ips = resolved
+	}
+
+	addresses := make([]string, 0, len(ips))
+	for _, address := range ips {
+		addresses = append(addresses, net.JoinHostPort(address, c.port))
+	}
+
+	return addresses, nil
+}
+
+// ParentZone walks up from name and returns the closest enclosing zone
+// together with the host names of its name servers.
+func (c *Client) ParentZone(ctx context.Context, name string) (string, []string, error) {
+	labels := dns.SplitDomainName(name)
+
+	for i := 1; i < len(labels); i++ {
+		zone := strings.Join(labels[i:], ".")
+		records, err := c.Resolve(ctx, zone, dns.TypeNS)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var hosts []string
+		for _, rr := range records {
+			if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, dns.Fqdn(zone)) {
+				hosts = append(hosts, CanonicalHost(ns.Ns))
+			}
+		}
+
+		if len(hosts) > 0 {
+			return zone, hosts, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("could not find the parent zone of %s", name)
+}
+
+// Delegation asks server, without recursion, for the NS set of name and
+// returns the name server host names and the lowest TTL seen. Both referrals
+// and authoritative answers are accepted.
+func (c *Client) Delegation(ctx context.Context, server string, name string) ([]string, uint32, error) {
+	resp, err := c.Exchange(ctx, server, name, dns.TypeNS, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, 0, fmt.Errorf("%s answered %s for %s NS", server, dns.RcodeToString[resp.Rcode], name)
+	}
+
+	var hosts []string
+	var ttl uint32
+	for _, rr := range append(resp.Answer, resp.Ns...) {
+		ns, ok := rr.(*dns.NS)
+		if !ok || !strings.EqualFold(ns.Hdr.Name, dns.Fqdn(name)) {
+			continue
+		}
+
+		hosts = append(hosts, CanonicalHost(ns.Ns))
+		if ttl == 0 || ns.Hdr.Ttl < ttl {
+			ttl = ns.Hdr.Ttl
+		}
+	}
+
+	if len(hosts) == 0 {
+		return nil, 0, fmt.Errorf("%s returned no delegation for %s", server, name)
+	}
+
+	return hosts, ttl, nil
+}
+
+// CheckAuthoritative verifies that server answers authoritatively with the
+// SOA and NS records of zone.
+func (c *Client) CheckAuthoritative(ctx context.Context, server string, zone string) error {
+	for _, qtype := range []uint16{dns.TypeSOA, dns.TypeNS} {
+		resp, err := c.Exchange(ctx, server, zone, qtype, false)
+		if err != nil {
+			return err
+		}
+
+		if resp.Rcode != dns.RcodeSuccess {
+			return fmt.Errorf("%s answered %s for %s %s", server, dns.RcodeToString[resp.Rcode], zone, dns.TypeToString[qtype])
+		}
+
+		if !resp.Authoritative {
+			return fmt.Errorf("%s is not authoritative for %s", server, zone)
+		}
+
+		if !hasRecord(resp.Answer, zone, qtype) {
+			return fmt.Errorf("%s returned no %s record for %s", server, dns.TypeToString[qtype], zone)
+		}
+	}
+
+	return nil
+}
+
+// Answers asks server, without recursion, for the records of name and
+// returns them sorted, in presentation format without owner name and TTL.
+// SOA records only keep their timers, the rest depends on the DNS provider.
+// A name that does not exist is answered as NXDOMAIN.
+func (c *Client) Answers(ctx context.Context, server string, name string, qtype uint16) ([]string, error) {
+	resp, err := c.Exchange(ctx, server, name, qtype, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Rcode == dns.RcodeNameError {
+		return []string{dns.RcodeToString[resp.Rcode]}, nil
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("%s answered %s for %s %s", server, dns.RcodeToString[resp.Rcode], name, dns.TypeToString[qtype])
+	}
+
+	answers := []string{}
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dns.SOA); ok {
+			answers = append(answers, fmt.Sprintf("SOA %d %d %d %d", soa.Refresh, soa.Retry, soa.Expire, soa.Minttl))
+			continue
+		}
+
+		// Domain names are case-insensitive, TXT data is not.
+		rdata := strings.TrimPrefix(rr.String(), rr.Header().String())
+		if rr.Header().Rrtype != dns.TypeTXT {
+			rdata = strings.ToLower(rdata)
+		}
+		answers = append(answers, dns.TypeToString[rr.Header().Rrtype]+" "+rdata)
+	}
+	sort.Strings(answers)
+
+	return answers, nil
+}
+
+// DNSKey is a DNSKEY record of a zone.
+type DNSKey struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+	PublicKey string
+	KeyTag    uint16
+}
+
+// DNSKeys asks server, without recursion, for the DNSKEY records of zone.
+func (c *Client) DNSKeys(ctx context.Context, server string, zone string) ([]DNSKey, error) {
+	resp, err := c.Exchange(ctx, server, zone, dns.TypeDNSKEY, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("%s answered %s for %s DNSKEY", server, dns.RcodeToString[resp.Rcode], zone)
+	}
+
+	keys := []DNSKey{}
+	for _, rr := range resp.Answer {
+		if key, ok := rr.(*dns.DNSKEY); ok {
+			keys = append(keys, DNSKey{
+				Flags:     key.Flags,
+				Protocol:  key.Protocol,
+				Algorithm: key.Algorithm,
+				PublicKey: key.PublicKey,
+				KeyTag:    key.KeyTag(),
+			})
+		}
+	}
+
+	return keys, nil
+}
+
+// ParseDNSKeys parses DNSKEY records of zone in presentation format, either
+// full records as printed by dig or a zone file, or only their rdata, eg:
+// "257 3 13 <public key>". Records of other types are ignored.
+func ParseDNSKeys(zone string, records string) ([]DNSKey, error) {
+	zone = dns.Fqdn(CanonicalHost(zone))
+
+	if strings.TrimSpace(records) == "" {
+		return nil, fmt.Errorf("no DNSKEY record found")
+	}
+
+	if !hasDNSKeyType(records) {
+		records = zone + " IN DNSKEY " + records
+	}
+
+	keys := []DNSKey{}
+	parser := dns.NewZoneParser(strings.NewReader(records), zone, "")
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		key, isKey := rr.(*dns.DNSKEY)
+		if !isKey {
+			continue
+		}
+
+		if !strings.EqualFold(key.Hdr.Name, zone) {
+			return nil, fmt.Errorf("DNSKEY record of %s is not a record of %s", key.Hdr.Name, zone)
+		}
+
+		if _, err := base64.StdEncoding.DecodeString(key.PublicKey); err != nil {
+			return nil, fmt.Errorf("public key of DNSKEY %d is not valid base64: %w", key.KeyTag(), err)
+		}
+
+		if key.PublicKey == "" {
+			return nil, fmt.Errorf("DNSKEY record of %s has no public key", key.Hdr.Name)
+		}
+
+		keys = append(keys, DNSKey{
+			Flags:     key.Flags,
+			Protocol:  key.Protocol,
+			Algorithm: key.Algorithm,
+			PublicKey: key.PublicKey,
+			KeyTag:    key.KeyTag(),
+		})
+	}
+
+	if err := parser.Err(); err != nil {
+		return nil, fmt.Errorf("could not parse DNSKEY records: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no DNSKEY record found")
+	}
+
+	return keys, nil
+}
+
+// DSDigest returns the hex digest of the DS record of key for zone, using
+// the digest type digestType, eg: dns.SHA256.
+func DSDigest(zone string, key DNSKey, digestType uint8) (string, error) {
+	dnskey := &dns.DNSKEY{
+		Hdr: dns.RR_Header{
+			Name:   dns.Fqdn(CanonicalHost(zone)),
+			Rrtype: dns.TypeDNSKEY,
+			Class:  dns.ClassINET,
+		},
+		Flags:     key.Flags,
+		Protocol:  key.Protocol,
+		Algorithm: key.Algorithm,
+		PublicKey: key.PublicKey,
+	}
+
+	ds := dnskey.ToDS(digestType)
+	if ds == nil {
+		return "", fmt.Errorf("could not compute the %s digest of DNSKEY %d", dns.HashToString[digestType], key.KeyTag)
+	}
+
+	return strings.ToUpper(ds.Digest), nil
+}
+
+func hasDNSKeyType(records string) bool {
+	for _, field := range strings.Fields(records) {
+		if strings.EqualFold(field, "DNSKEY") {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyTag computes the key tag of a DNSKEY from its flags, algorithm and
+// base64 public key, as published in DS records.
+func KeyTag(flags uint16, algorithm uint8, publicKey string) (uint16, error) {
+	publicKey = strings.Join(strings.Fields(publicKey), "")
+	if _, err := base64.StdEncoding.DecodeString(publicKey); err != nil {
+		return 0, fmt.Errorf("public key is not valid base64: %w", err)
+	}
+
+	key := &dns.DNSKEY{
+		Flags:     flags,
+		Protocol:  3,
+		Algorithm: algorithm,
+		PublicKey: publicKey,
+	}
+
+	return key.KeyTag(), nil
+}
+
+// ParseType returns the DNS record type named by qtype, eg: MX.
+func ParseType(qtype string) (uint16, error) {
+	value, ok := dns.StringToType[strings.ToUpper(qtype)]
+	if !ok {
+		return 0, fmt.Errorf("unknown DNS record type %q", qtype)
+	}
+
+	return value, nil
+}
+
+func hasRecord(records []dns.RR, name string, qtype uint16) bool {
+	for _, rr := range records {
+		if rr.Header().Rrtype == qtype && strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalHost lowercases host, strips the trailing dot and converts
+// internationalised names to punycode.
+func CanonicalHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		return ascii
+	}
+
+	return host
+}
@@ -0,0 +1,496 @@
+package dnsclient
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestNewClient(t *testing.T) {
+	testCases := map[string]struct {
+		cfg          Config
+		wantResolver string
+		wantPort     string
+		wantErr      bool
+	}{
+		"defaults":          {cfg: Config{Resolver: "192.0.2.53"}, wantResolver: "192.0.2.53:53", wantPort: "53"},
+		"resolver port":     {cfg: Config{Resolver: "192.0.2.53:5353"}, wantResolver: "192.0.2.53:5353", wantPort: "53"},
+		"IPv6 resolver":     {cfg: Config{Resolver: "2001:db8::53"}, wantResolver: "[2001:db8::53]:53", wantPort: "53"},
+		"name server port":  {cfg: Config{Resolver: "192.0.2.53", Port: 5300}, wantResolver: "192.0.2.53:53", wantPort: "5300"},
+		"negative port":     {cfg: Config{Resolver: "192.0.2.53", Port: -1}, wantErr: true},
+		"port out of range": {cfg: Config{Resolver: "192.0.2.53", Port: 65536}, wantErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewClient(testCase.cfg)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("NewClient(%+v) error = %v, want error %t", testCase.cfg, err, testCase.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if client.resolver != testCase.wantResolver || client.port != testCase.wantPort {
+				t.Errorf("NewClient(%+v) resolver, port = %q, %q, want %q, %q", testCase.cfg, client.resolver, client.port, testCase.wantResolver, testCase.wantPort)
+			}
+			if client.timeout != DefaultTimeout {
+				t.Errorf("NewClient(%+v) timeout = %s, want %s", testCase.cfg, client.timeout, DefaultTimeout)
+			}
+		})
+	}
+}
+
+func TestExchangeRetriesTruncatedAnswersOverTCP(t *testing.T) {
+	var udpQueries, tcpQueries atomic.Int32
+
+	address := startTestServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		if _, tcp := w.RemoteAddr().(*net.TCPAddr); !tcp {
+			udpQueries.Add(1)
+			resp.Truncated = true
+			w.WriteMsg(resp)
+			return
+		}
+
+		tcpQueries.Add(1)
+		resp.Answer = append(resp.Answer, &dns.TXT{Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 300}, Txt: []string{"over tcp"}})
+		w.WriteMsg(resp)
+	}))
+
+	client := newTestClient(t, address)
+	resp, err := client.Exchange(context.Background(), address, "Example.COM.", dns.TypeTXT, false)
+	if err != nil {
+		t.Fatalf("Exchange returned %q", err)
+	}
+
+	if udpQueries.Load() != 1 || tcpQueries.Load() != 1 {
+		t.Errorf("Exchange sent %d UDP and %d TCP queries, want 1 and 1", udpQueries.Load(), tcpQueries.Load())
+	}
+
+	if resp.Truncated || len(resp.Answer) != 1 {
+		t.Fatalf("Exchange returned %v, want the complete TCP answer", resp)
+	}
+
+	if got := resp.Question[0].Name; got != "example.com." {
+		t.Errorf("Exchange asked for %q, want the canonical name %q", got, "example.com.")
+	}
+}
+
+func TestExchangeTimeout(t *testing.T) {
+	address := startTestServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {}))
+
+	client, err := NewClient(Config{Resolver: address, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewClient returned %q", err)
+	}
+
+	_, err = client.Exchange(context.Background(), address, "example.com", dns.TypeSOA, false)
+	if err == nil || !strings.Contains(err.Error(), "querying "+address+" for example.com SOA") {
+		t.Errorf("Exchange error = %v, want a query error naming the server and question", err)
+	}
+}
+
+func TestCheckAuthoritative(t *testing.T) {
+	address := startTestServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		question := req.Question[0]
+		switch question.Name {
+		case "example.com.":
+			resp.Authoritative = true
+			resp.Answer = append(resp.Answer, testRecord(question.Name, question.Qtype))
+		case "lame.example.":
+			resp.Answer = append(resp.Answer, testRecord(question.Name, question.Qtype))
+		case "no-ns.example.":
+			resp.Authoritative = true
+			if question.Qtype == dns.TypeSOA {
+				resp.Answer = append(resp.Answer, testRecord(question.Name, question.Qtype))
+			}
+		case "other-owner.example.":
+			resp.Authoritative = true
+			resp.Answer = append(resp.Answer, testRecord("example.com.", question.Qtype))
+		default:
+			resp.Rcode = dns.RcodeRefused
+		}
+
+		w.WriteMsg(resp)
+	}))
+
+	testCases := map[string]struct {
+		zone    string
+		wantErr string
+	}{
+		"authoritative":     {zone: "example.com"},
+		"other notation":    {zone: "EXAMPLE.com."},
+		"not authoritative": {zone: "lame.example", wantErr: "is not authoritative for lame.example"},
+		"missing NS":        {zone: "no-ns.example", wantErr: "returned no NS record for no-ns.example"},
+		"other owner":       {zone: "other-owner.example", wantErr: "returned no SOA record for other-owner.example"},
+		"refused":           {zone: "unknown.example", wantErr: "answered REFUSED for unknown.example SOA"},
+	}
+
+	client := newTestClient(t, address)
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := client.CheckAuthoritative(context.Background(), address, testCase.zone)
+			checkError(t, err, testCase.wantErr)
+		})
+	}
+}
+
+func TestDelegation(t *testing.T) {
+	address := startTestServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		question := req.Question[0]
+		switch question.Name {
+		case "example.com.":
+			// Referral from the parent zone, with glue.
+			resp.Ns = append(resp.Ns,
+				&dns.NS{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 172800}, Ns: "NS1.Provider.net."},
+				&dns.NS{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 86400}, Ns: "ns2.provider.net."},
+			)
+			resp.Extra = append(resp.Extra, &dns.A{Hdr: dns.RR_Header{Name: "ns1.provider.net.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 172800}, A: net.ParseIP("192.0.2.1")})
+		case "example.net.":
+			// Authoritative answer from the zone itself.
+			resp.Authoritative = true
+			resp.Answer = append(resp.Answer, &dns.NS{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 3600}, Ns: "ns.example.net."})
+		case "example.org.":
+			// Referral to the parent zone only.
+			resp.Ns = append(resp.Ns, &dns.NS{Hdr: dns.RR_Header{Name: "org.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 172800}, Ns: "a0.org.afilias-nst.info."})
+		default:
+			resp.Rcode = dns.RcodeNameError
+		}
+
+		w.WriteMsg(resp)
+	}))
+
+	testCases := map[string]struct {
+		name      string
+		wantHosts []string
+		wantTTL   uint32
+		wantErr   string
+	}{
+		"referral":          {name: "example.com", wantHosts: []string{"ns1.provider.net", "ns2.provider.net"}, wantTTL: 86400},
+		"authoritative":     {name: "example.net", wantHosts: []string{"ns.example.net"}, wantTTL: 3600},
+		"other zone only":   {name: "example.org", wantErr: "returned no delegation for example.org"},
+		"domain not exists": {name: "example.invalid", wantErr: "answered NXDOMAIN for example.invalid NS"},
+	}
+
+	client := newTestClient(t, address)
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hosts, ttl, err := client.Delegation(context.Background(), address, testCase.name)
+			checkError(t, err, testCase.wantErr)
+
+			if !reflect.DeepEqual(hosts, testCase.wantHosts) || ttl != testCase.wantTTL {
+				t.Errorf("Delegation(%q) = %q, %d, want %q, %d", testCase.name, hosts, ttl, testCase.wantHosts, testCase.wantTTL)
+			}
+		})
+	}
+}
+
+func TestParentZone(t *testing.T) {
+	// The server acts as recursive resolver: only zones have NS records.
+	address := startTestServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.RecursionAvailable = true
+
+		question := req.Question[0]
+		switch question.Name {
+		case "co.uk.":
+			resp.Answer = append(resp.Answer, &dns.NS{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 172800}, Ns: "DNS1.Nic.UK."})
+		case "com.":
+			resp.Answer = append(resp.Answer, &dns.NS{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 172800}, Ns: "a.gtld-servers.net."})
+		case "shop.example.com.":
+			// A CNAME chain ending on a zone must not be mistaken for one.
+			resp.Answer = append(resp.Answer,
+				&dns.CNAME{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 300}, Target: "com."},
+				&dns.NS{Hdr: dns.RR_Header{Name: "com.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 172800}, Ns: "a.gtld-servers.net."},
+			)
+		case "invalid.":
+			resp.Rcode = dns.RcodeServerFailure
+		}
+
+		w.WriteMsg(resp)
+	}))
+
+	testCases := map[string]struct {
+		name      string
+		wantZone  string
+		wantHosts []string
+		wantErr   string
+	}{
+		"TLD":              {name: "example.com", wantZone: "com", wantHosts: []string{"a.gtld-servers.net"}},
+		"second level":     {name: "example.co.uk", wantZone: "co.uk", wantHosts: []string{"dns1.nic.uk"}},
+		"skips non zones":  {name: "www.shop.example.com", wantZone: "com", wantHosts: []string{"a.gtld-servers.net"}},
+		"resolver failure": {name: "example.invalid", wantErr: "resolving invalid NS: SERVFAIL"},
+		"no parent":        {name: "localhost", wantErr: "could not find the parent zone of localhost"},
+	}
+
+	client := newTestClient(t, address)
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			zone, hosts, err := client.ParentZone(context.Background(), testCase.name)
+			checkError(t, err, testCase.wantErr)
+
+			if zone != testCase.wantZone || !reflect.DeepEqual(hosts, testCase.wantHosts) {
+				t.Errorf("ParentZone(%q) = %q, %q, want %q, %q", testCase.name, zone, hosts, testCase.wantZone, testCase.wantHosts)
+			}
+		})
+	}
+}
+
+func TestCanonicalHost(t *testing.T) {
+	testCases := map[string]struct {
+		host string
+		want string
+	}{
+		"already canonical": {host: "ns1.example.com", want: "ns1.example.com"},
+		"trailing dot":      {host: "ns1.example.com.", want: "ns1.example.com"},
+		"uppercase":         {host: "NS1.Example.COM.", want: "ns1.example.com"},
+		"unicode":           {host: "ns1.Bücher.example", want: "ns1.xn--bcher-kva.example"},
+		"punycode":          {host: "ns1.xn--bcher-kva.example", want: "ns1.xn--bcher-kva.example"},
+		"invalid kept":      {host: "ns_1.example.com", want: "ns_1.example.com"},
+		"root":              {host: ".", want: ""},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := CanonicalHost(testCase.host); got != testCase.want {
+				t.Errorf("CanonicalHost(%q) = %q, want %q", testCase.host, got, testCase.want)
+			}
+		})
+	}
+}
+
+// startTestServer serves handler over UDP and TCP on the same random local
+// port until the test ends, and returns its host:port.
+func startTestServer(t *testing.T, handler dns.Handler) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen on UDP: %s", err)
+	}
+
+	listener, err := net.Listen("tcp", conn.LocalAddr().String())
+	if err != nil {
+		conn.Close()
+		t.Fatalf("could not listen on TCP: %s", err)
+	}
+
+	for _, server := range []*dns.Server{
+		{PacketConn: conn, Handler: handler},
+		{Listener: listener, Handler: handler},
+	} {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+		go server.ActivateAndServe()
+		t.Cleanup(func() { server.Shutdown() })
+		<-started
+	}
+
+	return conn.LocalAddr().String()
+}
+
+func newTestClient(t *testing.T, resolver string) *Client {
+	t.Helper()
+
+	client, err := NewClient(Config{Resolver: resolver, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewClient returned %q", err)
+	}
+
+	return client
+}
+
+// testRecord returns a SOA or NS record of zone.
+func testRecord(zone string, qtype uint16) dns.RR {
+	header := dns.RR_Header{Name: zone, Rrtype: qtype, Class: dns.ClassINET, Ttl: 3600}
+	if qtype == dns.TypeSOA {
+		return &dns.SOA{Hdr: header, Ns: "ns1." + zone, Mbox: "hostmaster." + zone, Serial: 1, Refresh: 7200, Retry: 3600, Expire: 1209600, Minttl: 300}
+	}
+
+	return &dns.NS{Hdr: header, Ns: "ns1." + zone}
+}
+
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	switch {
+	case want == "" && err != nil:
+		t.Fatalf("unexpected error %q", err)
+	case want != "" && err == nil:
+		t.Fatalf("no error, want one containing %q", want)
+	case want != "" && !strings.Contains(err.Error(), want):
+		t.Fatalf("error %q does not contain %q", err, want)
+	}
+}
+
+// rfc4034Key is the DNSKEY of dskey.example.com. from RFC 4034 section 5.4,
+// also used by RFC 4509 section 2.3.
+const rfc4034Key = `AQOeiiR0GOMYkDshWoSKz9Xz
+	fwJr1AYtsmx3TGkJaNXVbfi/
+	2pHm822aJ5iI9BMzNXxeYCmZ
+	DRD99WYwYqUSdjMmmAphXdvx
+	egXd/M5+X7OrzKBaMbCVdFLU
+	Uh6DhweJBjEVv5f2wwjM9Xzc
+	nOf+EPbtG9DMBmADjFDc2w/r
+	ljwvFw==`
+
+// rfc6605Key13 and rfc6605Key14 are the DNSKEYs of example.net. from RFC 6605
+// section 6.
+const (
+	rfc6605Key13 = "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="
+	rfc6605Key14 = "xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40"
+)
+
+func TestKeyTag(t *testing.T) {
+	testCases := map[string]struct {
+		flags     uint16
+		algorithm uint8
+		publicKey string
+		want      uint16
+		wantErr   bool
+	}{
+		"RFC 4034 zone signing key":   {flags: 256, algorithm: 5, publicKey: rfc4034Key, want: 60485},
+		"RFC 6605 ECDSA P-256 SHA256": {flags: 257, algorithm: 13, publicKey: rfc6605Key13, want: 55648},
+		"RFC 6605 ECDSA P-384 SHA384": {flags: 257, algorithm: 14, publicKey: rfc6605Key14, want: 10771},
+		"other flags":                 {flags: 257, algorithm: 5, publicKey: rfc4034Key, want: 60486},
+		"invalid base64":              {flags: 257, algorithm: 13, publicKey: "not base64!", wantErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := KeyTag(testCase.flags, testCase.algorithm, testCase.publicKey)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("KeyTag error = %v, want error %t", err, testCase.wantErr)
+			}
+
+			if got != testCase.want {
+				t.Errorf("KeyTag(%d, %d, ...) = %d, want %d", testCase.flags, testCase.algorithm, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestDSDigest(t *testing.T) {
+	rfc4034 := DNSKey{Flags: 256, Protocol: 3, Algorithm: 5, PublicKey: strings.Join(strings.Fields(rfc4034Key), "")}
+
+	testCases := map[string]struct {
+		zone       string
+		key        DNSKey
+		digestType uint8
+		want       string
+	}{
+		"RFC 4034 SHA-1":           {zone: "dskey.example.com", key: rfc4034, digestType: dns.SHA1, want: "2BB183AF5F22588179A53B0A98631FAD1A292118"},
+		"RFC 4509 SHA-256":         {zone: "dskey.example.com", key: rfc4034, digestType: dns.SHA256, want: "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"},
+		"RFC 6605 SHA-256":         {zone: "example.net", key: DNSKey{Flags: 257, Protocol: 3, Algorithm: 13, PublicKey: rfc6605Key13}, digestType: dns.SHA256, want: "B4C8C1FE2E7477127B27115656AD6256F424625BF5C1E2770CE6D6E37DF61D17"},
+		"RFC 6605 SHA-384":         {zone: "example.net", key: DNSKey{Flags: 257, Protocol: 3, Algorithm: 14, PublicKey: rfc6605Key14}, digestType: dns.SHA384, want: "72D7B62976CE06438E9C0BF319013CF801F09ECC84B8D7E9495F27E305C6A9B0563A9B5F4D288405C3008A946DF983D6"},
+		"zone in another notation": {zone: "DSKEY.Example.COM.", key: rfc4034, digestType: dns.SHA256, want: "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := DSDigest(testCase.zone, testCase.key, testCase.digestType)
+			if err != nil {
+				t.Fatalf("DSDigest returned %q", err)
+			}
+
+			if got != testCase.want {
+				t.Errorf("DSDigest(%q, ..., %d) = %s, want %s", testCase.zone, testCase.digestType, got, testCase.want)
+			}
+		})
+	}
+
+	if _, err := DSDigest("example.net", rfc4034, 0); err == nil {
+		t.Errorf("DSDigest with digest type 0 returned no error")
+	}
+}
+
+func TestParseDNSKeys(t *testing.T) {
+	rfc4034 := DNSKey{Flags: 256, Protocol: 3, Algorithm: 5, PublicKey: strings.Join(strings.Fields(rfc4034Key), ""), KeyTag: 60485}
+	rfc6605 := DNSKey{Flags: 257, Protocol: 3, Algorithm: 13, PublicKey: rfc6605Key13, KeyTag: 55648}
+
+	testCases := map[string]struct {
+		zone    string
+		records string
+		want    []DNSKey
+		wantErr string
+	}{
+		"zone file record": {
+			zone:    "dskey.example.com",
+			records: "dskey.example.com. 86400 IN DNSKEY 256 3 5 ( " + rfc4034Key + " ) ; key id = 60485",
+			want:    []DNSKey{rfc4034},
+		},
+		"rdata only": {
+			zone:    "example.net",
+			records: "257 3 13 " + rfc6605Key13,
+			want:    []DNSKey{rfc6605},
+		},
+		"dig output": {
+			zone: "Example.NET.",
+			records: "; <<>> DiG <<>> example.net DNSKEY\n" +
+				"example.net.\t3600\tIN\tDNSKEY\t257 3 13 " + rfc6605Key13 + "\n" +
+				"example.net.\t3600\tIN\tRRSIG\tDNSKEY 13 2 3600 20100909102025 20100812102025 55648 example.net. qx6wLYqmh+l9oCKTN6qIc+bw6ya+KJ8oMz0YP107epXAyGmt+3SNruPFKG7tZoLBLlUzGGus7ZwmwWep666VCw==\n" +
+				"example.net.\t3600\tIN\tDNSKEY\t256 3 13 " + rfc6605Key13 + "\n",
+			want: []DNSKey{rfc6605, {Flags: 256, Protocol: 3, Algorithm: 13, PublicKey: rfc6605Key13, KeyTag: 55647}},
+		},
+		"record of another zone": {
+			zone:    "example.com",
+			records: "example.net. IN DNSKEY 257 3 13 " + rfc6605Key13,
+			wantErr: "DNSKEY record of example.net. is not a record of example.com.",
+		},
+		"invalid base64": {
+			zone:    "example.net",
+			records: "257 3 13 AAAA!",
+			wantErr: "public key of DNSKEY 0 is not valid base64",
+		},
+		"missing public key": {
+			zone:    "example.net",
+			records: "257 3 13",
+			wantErr: "DNSKEY record of example.net. has no public key",
+		},
+		"invalid rdata": {
+			zone:    "example.net",
+			records: "example.net. IN A 192.0.2.1",
+			wantErr: "could not parse DNSKEY records",
+		},
+		"signatures only": {
+			zone:    "example.net",
+			records: "example.net. 3600 IN RRSIG DNSKEY 13 2 3600 20100909102025 20100812102025 55648 example.net. qx6wLYqmh+l9oCKTN6qIc+bw6ya+KJ8oMz0YP107epXAyGmt+3SNruPFKG7tZoLBLlUzGGus7ZwmwWep666VCw==",
+			wantErr: "no DNSKEY record found",
+		},
+		"empty": {
+			zone:    "example.net",
+			records: " \n",
+			wantErr: "no DNSKEY record found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseDNSKeys(testCase.zone, testCase.records)
+			checkError(t, err, testCase.wantErr)
+
+			if testCase.wantErr == "" && !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("ParseDNSKeys(%q, ...) = %+v, want %+v", testCase.zone, got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &CDCDomainAuthInfoEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &CDCDomainAuthInfoEphemeralResource{}
+
+func NewCDCDomainAuthInfoEphemeralResource() ephemeral.EphemeralResource {
+	return &CDCDomainAuthInfoEphemeralResource{}
+}
+
+type CDCDomainAuthInfoEphemeralResource struct {
+	client *api.APIClient
+}
+
+type CDCDomainAuthInfoEphemeralResourceModel struct {
+	ServiceName HostnameValue `tfsdk:"service_name"`
+	AuthInfo    types.String  `tfsdk:"auth_info"`
+}
+
+func (r *CDCDomainAuthInfoEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_auth_info"
+}
+
+func (r *CDCDomainAuthInfoEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "OVH domain auth info ephemeral resource. Reads the auth code (EPP code) used to transfer a domain to another registrar, without storing it in state or plan files.",
+
+		Attributes: map[string]schema.Attribute{
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+			},
+			"auth_info": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Auth code of the domain",
+			},
+		},
+	}
+}
+
+func (r *CDCDomainAuthInfoEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CDCDomainAuthInfoEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data *CDCDomainAuthInfoEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	authInfo, err := r.client.GetAuthInfo(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("OPEN: Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		detail := "OPEN: Could not read auth info, unexpected error: " + err.Error()
+		if domain, domainErr := r.client.GetDomain(serviceName); domainErr == nil && domain.TransferLockStatus != api.TransferLockUnlocked {
+			detail += fmt.Sprintf(". The transfer lock of %s is %s, registries usually only give the auth code of unlocked domains", serviceName, domain.TransferLockStatus)
+		}
+
+		resp.Diagnostics.AddError(
+			"Error reading auth info",
+			detail,
+		)
+		return
+	}
+
+	data.AuthInfo = types.StringValue(authInfo)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDomainAuthInfoOpen(t *testing.T) {
+	testCases := map[string]struct {
+		authInfo     string
+		authStatus   int
+		lockStatus   string
+		wantAuthInfo string
+		wantSummary  string
+		wantPath     path.Path
+		wantDetail   string
+	}{
+		"unlocked domain": {
+			authInfo:     `"s3cr3t-Auth"`,
+			authStatus:   http.StatusOK,
+			wantAuthInfo: "s3cr3t-Auth",
+		},
+		"domain not found": {
+			authInfo:    `{"message":"The requested object (serviceName = example.com) does not exist"}`,
+			authStatus:  http.StatusNotFound,
+			wantSummary: "Domain not found",
+			wantPath:    path.Root("service_name"),
+		},
+		"locked domain": {
+			authInfo:    `{"message":"Domain is locked"}`,
+			authStatus:  http.StatusForbidden,
+			lockStatus:  api.TransferLockLocked,
+			wantSummary: "Error reading auth info",
+			wantDetail:  "The transfer lock of example.com is locked",
+		},
+		"other error": {
+			authInfo:    `{"message":"Internal server error"}`,
+			authStatus:  http.StatusInternalServerError,
+			lockStatus:  api.TransferLockUnlocked,
+			wantSummary: "Error reading auth info",
+			wantDetail:  "Internal server error",
+		},
+	}
+
+	ctx := context.Background()
+
+	schemaResp := &ephemeral.SchemaResponse{}
+	NewCDCDomainAuthInfoEphemeralResource().Schema(ctx, ephemeral.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/domain/example.com/authInfo":
+					w.WriteHeader(testCase.authStatus)
+					fmt.Fprint(w, testCase.authInfo)
+				case "/domain/example.com":
+					fmt.Fprintf(w, `{"domain":"example.com","transferLockStatus":%q}`, testCase.lockStatus)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"not found"}`)
+				}
+			}))
+
+			req := ephemeral.OpenRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+						"service_name": tftypes.NewValue(tftypes.String, "Example.COM."),
+						"auth_info":    tftypes.NewValue(tftypes.String, nil),
+					}),
+				},
+			}
+			resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema, Raw: req.Config.Raw.Copy()}}
+
+			resource := &CDCDomainAuthInfoEphemeralResource{client: client}
+			resource.Open(ctx, req, resp)
+
+			if testCase.wantSummary == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+
+				var authInfo types.String
+				resp.Diagnostics.Append(resp.Result.GetAttribute(ctx, path.Root("auth_info"), &authInfo)...)
+				if authInfo.ValueString() != testCase.wantAuthInfo {
+					t.Errorf("auth_info = %s, want %q", authInfo, testCase.wantAuthInfo)
+				}
+				return
+			}
+
+			errors := resp.Diagnostics.Errors()
+			if len(errors) != 1 || errors[0].Summary() != testCase.wantSummary {
+				t.Fatalf("got diagnostics %v, want a single %q error", resp.Diagnostics, testCase.wantSummary)
+			}
+
+			if !strings.Contains(errors[0].Detail(), testCase.wantDetail) {
+				t.Errorf("detail %q does not contain %q", errors[0].Detail(), testCase.wantDetail)
+			}
+
+			if testCase.lockStatus == api.TransferLockUnlocked && strings.Contains(errors[0].Detail(), "transfer lock") {
+				t.Errorf("detail %q mentions the transfer lock of an unlocked domain", errors[0].Detail())
+			}
+
+			if len(testCase.wantPath.Steps()) > 0 {
+				withPath, ok := errors[0].(diag.DiagnosticWithPath)
+				if !ok || !withPath.Path().Equal(testCase.wantPath) {
+					t.Errorf("diagnostic %q is not reported on %s", errors[0].Summary(), testCase.wantPath)
+				}
+			}
+		})
+	}
+}
+
+// newTestAPIClient returns a client of an OVH API served by handler until the
+// test ends. The calls made by api.GetClient are answered here.
+func newTestAPIClient(t *testing.T, handler http.Handler) *api.APIClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/time":
+			fmt.Fprint(w, time.Now().Unix())
+		case "/auth/currentCredential":
+			fmt.Fprint(w, `{"status":"validated"}`)
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := api.GetClient(api.OVHCredentials{
+		Endpoint:          server.URL,
+		ApplicationKey:    "application-key",
+		ApplicationSecret: "application-secret",
+		ConsumerKey:       "consumer-key",
+	}, context.Background())
+	if err != nil {
+		t.Fatalf("could not create the API client: %s", err)
+	}
+
+	return client
+}
@@ -0,0 +1,440 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CDCDomainContactsResource{}
+var _ resource.ResourceWithImportState = &CDCDomainContactsResource{}
+var _ resource.ResourceWithModifyPlan = &CDCDomainContactsResource{}
+var _ resource.ResourceWithValidateConfig = &CDCDomainContactsResource{}
+
+func NewCDCDomainContactsResource() resource.Resource {
+	return &CDCDomainContactsResource{}
+}
+
+type CDCDomainContactsResource struct {
+	client *api.APIClient
+}
+
+type CDCDomainContactsResourceModel struct {
+	ID          types.String  `tfsdk:"id"`
+	ServiceName HostnameValue `tfsdk:"service_name"`
+	Owner       types.String  `tfsdk:"owner"`
+	Admin       types.String  `tfsdk:"admin"`
+	Tech        types.String  `tfsdk:"tech"`
+	Billing     types.String  `tfsdk:"billing"`
+}
+
+func (r *CDCDomainContactsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_contacts"
+}
+
+func (r *CDCDomainContactsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	contactAttribute := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			Optional:    true,
+			Computed:    true,
+			Description: description,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
+			},
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "OVH domain contacts resource. Changes the admin, tech and billing contacts of a domain, destroying it leaves them as they are.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					hostnameRequiresReplace(),
+				},
+			},
+			"owner": schema.StringAttribute{
+				Computed:    true,
+				Description: "Owner contact of the domain (whoisOwner). Changing the owner is a trade done in the OVH Panel, a change is reported when refreshing",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"admin":   contactAttribute("NIC handle of the administrative contact, eg: ab12345-ovh. Defaults to the current contact."),
+			"tech":    contactAttribute("NIC handle of the technical contact, eg: ab12345-ovh. Defaults to the current contact."),
+			"billing": contactAttribute("NIC handle of the billing contact, eg: ab12345-ovh. Defaults to the current contact."),
+		},
+	}
+}
+
+func (r *CDCDomainContactsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *CDCDomainContactsResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ServiceName.IsNull() && !data.ServiceName.IsUnknown() {
+		_, diags := parseServiceName(data.ServiceName)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *CDCDomainContactsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CDCDomainContactsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *CDCDomainContactsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// Nothing to check on destroy, or before the values are known.
+	if plan == nil || plan.ServiceName.IsUnknown() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state != nil && !contactsChanged(plan, state.Admin, state.Tech, state.Billing) {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading domain",
+			"Could not read domain state, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	serviceInfos, err := r.client.GetServiceInfos(serviceName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading service infos",
+			"Could not read domain contacts, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if plan.Owner.IsUnknown() {
+		plan.Owner = types.StringValue(domain.WhoisOwner)
+	}
+	plan.Admin = plannedContact(plan.Admin, serviceInfos.ContactAdmin)
+	plan.Tech = plannedContact(plan.Tech, serviceInfos.ContactTech)
+	plan.Billing = plannedContact(plan.Billing, serviceInfos.ContactBilling)
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+
+	if !contactsChanged(plan, types.StringValue(serviceInfos.ContactAdmin), types.StringValue(serviceInfos.ContactTech), types.StringValue(serviceInfos.ContactBilling)) {
+		return
+	}
+
+	resp.Diagnostics.Append(domainStateDiagnostics(domain, true)...)
+
+	pendingTasks, err := r.client.PendingContactChangeTasks(serviceName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading contact change tasks",
+			"Could not check for pending contact changes, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	for _, task := range pendingTasks {
+		resp.Diagnostics.AddError(
+			"Contact change already pending",
+			fmt.Sprintf("Contact change %d of %s (%s, %s to %s) is %s. Validate or cancel it before changing contacts again", task.ID, serviceName, strings.Join(task.ContactTypes, ", "), task.FromAccount, task.ToAccount, task.State),
+		)
+	}
+}
+
+func (r *CDCDomainContactsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CDCDomainContactsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	resp.Diagnostics.Append(r.applyContacts(ctx, serviceName, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainContactsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CDCDomainContactsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Domain not found",
+			fmt.Sprintf("READ: Domain %s is not in the OVH account anymore. Removing its contacts from state", serviceName),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading contacts",
+			"READ: Could not read domain, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	serviceInfos, err := r.client.GetServiceInfos(serviceName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading contacts",
+			"READ: Could not read domain contacts, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if !data.Owner.IsNull() && !strings.EqualFold(data.Owner.ValueString(), domain.WhoisOwner) {
+		resp.Diagnostics.AddWarning(
+			"Domain owner changed",
+			fmt.Sprintf("READ: The owner of %s changed from %s to %s", serviceName, data.Owner.ValueString(), domain.WhoisOwner),
+		)
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	data.Owner = types.StringValue(domain.WhoisOwner)
+	setContactsFromServiceInfos(data, serviceInfos)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainContactsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *CDCDomainContactsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.applyContacts(ctx, serviceName, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state, contacts cannot be unset.
+func (r *CDCDomainContactsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *CDCDomainContactsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	serviceName, err := api.ParseDomainName(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected a domain name: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), serviceName.String())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), req.ID)...)
+}
+
+// applyContacts asks for the contacts of data that differ from the current
+// ones and waits for the contact change tasks. Changes waiting for the
+// contacts to validate them by email are reported as a warning, data keeps
+// the planned contacts so the next refresh shows whether they were validated.
+func (r *CDCDomainContactsResource) applyContacts(ctx context.Context, serviceName api.DomainName, data *CDCDomainContactsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	serviceInfos, err := r.client.GetServiceInfos(serviceName)
+	if err != nil {
+		diags.AddError(
+			"Error changing contacts",
+			"Could not read domain contacts, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	request := api.ContactChangeRequest{}
+	if !strings.EqualFold(data.Admin.ValueString(), serviceInfos.ContactAdmin) {
+		request.ContactAdmin = data.Admin.ValueString()
+	}
+	if !strings.EqualFold(data.Tech.ValueString(), serviceInfos.ContactTech) {
+		request.ContactTech = data.Tech.ValueString()
+	}
+	if !strings.EqualFold(data.Billing.ValueString(), serviceInfos.ContactBilling) {
+		request.ContactBilling = data.Billing.ValueString()
+	}
+
+	if request == (api.ContactChangeRequest{}) {
+		return diags
+	}
+
+	ids, err := r.client.ChangeContact(serviceName, request)
+	if err != nil {
+		diags.AddError(
+			"Error changing contacts",
+			"Could not change domain contacts, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	for _, id := range ids {
+		task, err := waitForContactChange(ctx, r.client, id)
+		if err != nil {
+			diags.AddError(
+				"Error changing contacts",
+				fmt.Sprintf("Contact change %d did not complete: %s", id, err.Error()),
+			)
+			return diags
+		}
+
+		if task.State == api.ContactChangeValidatingByCustomers {
+			diags.AddWarning(
+				"Contact change waiting for validation",
+				fmt.Sprintf("Contact change %d of %s (%s) waits for the contacts to validate it by email. Until then, refreshing shows the previous contacts", id, serviceName, strings.Join(task.ContactTypes, ", ")),
+			)
+		}
+	}
+
+	return diags
+}
+
+// waitForContactChange polls a contact change task until it is done or waits
+// for the contacts to validate it.
+func waitForContactChange(ctx context.Context, client *api.APIClient, id int64) (api.ContactChangeTask, error) {
+	for {
+		task, err := client.GetContactChangeTask(id)
+		if err != nil {
+			return task, err
+		}
+
+		switch task.State {
+		case api.ContactChangeDone, api.ContactChangeValidatingByCustomers:
+			return task, nil
+		case api.ContactChangeTodo, api.ContactChangeDoing, api.ContactChangeCheckValidity:
+		default:
+			return task, fmt.Errorf("task state %s. check OVH Panel", task.State)
+		}
+
+		select {
+		case <-ctx.Done():
+			return task, fmt.Errorf("stopped waiting for contact change %d: %w", id, ctx.Err())
+		case <-time.After(api.CHECK_STATUS_WAIT_TIME):
+		}
+	}
+}
+
+// setContactsFromServiceInfos sets the contacts from the API, keeping the
+// notation of contacts that only differ by case.
+func setContactsFromServiceInfos(data *CDCDomainContactsResourceModel, serviceInfos api.ServiceInfos) {
+	data.Admin = currentContact(data.Admin, serviceInfos.ContactAdmin)
+	data.Tech = currentContact(data.Tech, serviceInfos.ContactTech)
+	data.Billing = currentContact(data.Billing, serviceInfos.ContactBilling)
+}
+
+func currentContact(prior types.String, current string) types.String {
+	if strings.EqualFold(prior.ValueString(), current) {
+		return prior
+	}
+	return types.StringValue(current)
+}
+
+func plannedContact(planned types.String, current string) types.String {
+	if planned.IsUnknown() {
+		return types.StringValue(current)
+	}
+	return planned
+}
+
+func contactsChanged(plan *CDCDomainContactsResourceModel, admin, tech, billing types.String) bool {
+	changed := func(planned, current types.String) bool {
+		return !planned.IsUnknown() && !strings.EqualFold(planned.ValueString(), current.ValueString())
+	}
+
+	return changed(plan.Admin, admin) || changed(plan.Tech, tech) || changed(plan.Billing, billing)
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSetContactsFromServiceInfos(t *testing.T) {
+	testCases := map[string]struct {
+		prior CDCDomainContactsResourceModel
+		want  CDCDomainContactsResourceModel
+	}{
+		"same contacts": {
+			prior: contactsModel(types.StringValue("ab1234-ovh"), types.StringValue("cd5678-ovh"), types.StringValue("ab1234-ovh")),
+			want:  contactsModel(types.StringValue("ab1234-ovh"), types.StringValue("cd5678-ovh"), types.StringValue("ab1234-ovh")),
+		},
+		"prior notation kept": {
+			prior: contactsModel(types.StringValue("AB1234-OVH"), types.StringValue("cd5678-ovh"), types.StringValue("Ab1234-Ovh")),
+			want:  contactsModel(types.StringValue("AB1234-OVH"), types.StringValue("cd5678-ovh"), types.StringValue("Ab1234-Ovh")),
+		},
+		"changed outside terraform": {
+			prior: contactsModel(types.StringValue("ab1234-ovh"), types.StringValue("ef9012-ovh"), types.StringValue("ab1234-ovh")),
+			want:  contactsModel(types.StringValue("ab1234-ovh"), types.StringValue("cd5678-ovh"), types.StringValue("ab1234-ovh")),
+		},
+		"imported": {
+			prior: contactsModel(types.StringNull(), types.StringNull(), types.StringNull()),
+			want:  contactsModel(types.StringValue("ab1234-ovh"), types.StringValue("cd5678-ovh"), types.StringValue("ab1234-ovh")),
+		},
+	}
+
+	serviceInfos := api.ServiceInfos{ContactAdmin: "ab1234-ovh", ContactTech: "cd5678-ovh", ContactBilling: "ab1234-ovh"}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := testCase.prior
+			setContactsFromServiceInfos(&data, serviceInfos)
+
+			if data != testCase.want {
+				t.Errorf("setContactsFromServiceInfos = %+v, want %+v", data, testCase.want)
+			}
+		})
+	}
+}
+
+func TestPlannedContact(t *testing.T) {
+	testCases := map[string]struct {
+		planned types.String
+		want    types.String
+	}{
+		"configured":   {planned: types.StringValue("ef9012-ovh"), want: types.StringValue("ef9012-ovh")},
+		"unconfigured": {planned: types.StringUnknown(), want: types.StringValue("ab1234-ovh")},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := plannedContact(testCase.planned, "ab1234-ovh"); !got.Equal(testCase.want) {
+				t.Errorf("plannedContact(%s) = %s, want %s", testCase.planned, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestContactsChanged(t *testing.T) {
+	admin, tech, billing := types.StringValue("ab1234-ovh"), types.StringValue("cd5678-ovh"), types.StringValue("ab1234-ovh")
+
+	testCases := map[string]struct {
+		plan CDCDomainContactsResourceModel
+		want bool
+	}{
+		"unchanged":        {plan: contactsModel(admin, tech, billing), want: false},
+		"other case":       {plan: contactsModel(types.StringValue("AB1234-OVH"), tech, billing), want: false},
+		"unknown skipped":  {plan: contactsModel(types.StringUnknown(), types.StringUnknown(), types.StringUnknown()), want: false},
+		"admin changed":    {plan: contactsModel(types.StringValue("ef9012-ovh"), tech, billing), want: true},
+		"tech changed":     {plan: contactsModel(admin, types.StringValue("ef9012-ovh"), billing), want: true},
+		"billing changed":  {plan: contactsModel(admin, tech, types.StringValue("ef9012-ovh")), want: true},
+		"one known change": {plan: contactsModel(types.StringUnknown(), types.StringValue("ef9012-ovh"), types.StringUnknown()), want: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := contactsChanged(&testCase.plan, admin, tech, billing); got != testCase.want {
+				t.Errorf("contactsChanged(%+v) = %t, want %t", testCase.plan, got, testCase.want)
+			}
+		})
+	}
+}
+
+func contactsModel(admin, tech, billing types.String) CDCDomainContactsResourceModel {
+	return CDCDomainContactsResourceModel{Admin: admin, Tech: tech, Billing: billing}
+}
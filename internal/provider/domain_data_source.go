@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CDCDomainDataSource{}
+
+func NewCDCDomainDataSource() datasource.DataSource {
+	return &CDCDomainDataSource{}
+}
+
+type CDCDomainDataSource struct {
+	client *api.APIClient
+}
+
+type CDCDomainDataSourceModel struct {
+	ServiceName                HostnameValue                `tfsdk:"service_name"`
+	NameServerType             types.String                 `tfsdk:"name_server_type"`
+	Offer                      types.String                 `tfsdk:"offer"`
+	State                      types.String                 `tfsdk:"state"`
+	SuspensionState            types.String                 `tfsdk:"suspension_state"`
+	TransferLockStatus         types.String                 `tfsdk:"transfer_lock_status"`
+	WhoisOwner                 types.String                 `tfsdk:"whois_owner"`
+	LastUpdate                 types.String                 `tfsdk:"last_update"`
+	DNSSECSupported            types.Bool                   `tfsdk:"dnssec_supported"`
+	OwoSupported               types.Bool                   `tfsdk:"owo_supported"`
+	GlueRecordIPv6Supported    types.Bool                   `tfsdk:"glue_record_ipv6_supported"`
+	GlueRecordMultiIPSupported types.Bool                   `tfsdk:"glue_record_multi_ip_supported"`
+	HostSupported              types.Bool                   `tfsdk:"host_supported"`
+	ParentService              *CDCDomainParentServiceModel `tfsdk:"parent_service"`
+	ServiceStatus              types.String                 `tfsdk:"service_status"`
+	Creation                   types.String                 `tfsdk:"creation"`
+	Expiration                 types.String                 `tfsdk:"expiration"`
+	Expired                    types.Bool                   `tfsdk:"expired"`
+	RenewMode                  types.String                 `tfsdk:"renew_mode"`
+	RenewPeriod                types.Int64                  `tfsdk:"renew_period"`
+	PossibleRenewPeriods       []types.Int64                `tfsdk:"possible_renew_periods"`
+	DeleteAtExpiration         types.Bool                   `tfsdk:"delete_at_expiration"`
+	ContactAdmin               types.String                 `tfsdk:"contact_admin"`
+	ContactTech                types.String                 `tfsdk:"contact_tech"`
+	ContactBilling             types.String                 `tfsdk:"contact_billing"`
+}
+
+type CDCDomainParentServiceModel struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+func (d *CDCDomainDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain"
+}
+
+func (d *CDCDomainDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "OVH domain data source. Reads the registrar and billing details of a domain.",
+
+		Attributes: map[string]schema.Attribute{
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+			},
+			"name_server_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name server type: hosted or external",
+			},
+			"offer": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain offer, eg: gold",
+			},
+			"state": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain state, eg: ok, expired, pendingDelete",
+			},
+			"suspension_state": schema.StringAttribute{
+				Computed:    true,
+				Description: "Suspension state, eg: not_suspended",
+			},
+			"transfer_lock_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Transfer lock status: locked, locking, unlocked, unlocking or unavailable",
+			},
+			"whois_owner": schema.StringAttribute{
+				Computed:    true,
+				Description: "Owner contact of the domain",
+			},
+			"last_update": schema.StringAttribute{
+				Computed:    true,
+				Description: "Last update of the domain",
+			},
+			"dnssec_supported": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the registry accepts DS records",
+			},
+			"owo_supported": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether WHOIS fields can be obfuscated",
+			},
+			"glue_record_ipv6_supported": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether glue records can have IPv6 addresses",
+			},
+			"glue_record_multi_ip_supported": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether glue records can have several addresses",
+			},
+			"host_supported": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether glue records are supported",
+			},
+			"parent_service": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "Service the domain belongs to, if any",
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Computed:    true,
+						Description: "Parent service name",
+					},
+					"type": schema.StringAttribute{
+						Computed:    true,
+						Description: "Parent service type",
+					},
+				},
+			},
+			"service_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Billing status of the domain, eg: ok, expired",
+			},
+			"creation": schema.StringAttribute{
+				Computed:    true,
+				Description: "Creation date of the domain",
+			},
+			"expiration": schema.StringAttribute{
+				Computed:    true,
+				Description: "Expiration date of the domain",
+			},
+			"expired": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the expiration date is in the past",
+			},
+			"renew_mode": schema.StringAttribute{
+				Computed:    true,
+				Description: "Renewal mode: automatic or manual",
+			},
+			"renew_period": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Renewal period in months",
+			},
+			"possible_renew_periods": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.Int64Type,
+				Description: "Renewal periods offered by OVH, in months",
+			},
+			"delete_at_expiration": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the domain is deleted when it expires",
+			},
+			"contact_admin": schema.StringAttribute{
+				Computed:    true,
+				Description: "NIC handle of the administrative contact",
+			},
+			"contact_tech": schema.StringAttribute{
+				Computed:    true,
+				Description: "NIC handle of the technical contact",
+			},
+			"contact_billing": schema.StringAttribute{
+				Computed:    true,
+				Description: "NIC handle of the billing contact",
+			},
+		},
+	}
+}
+
+func (d *CDCDomainDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *CDCDomainDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *CDCDomainDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := d.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("READ: Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading domain",
+			"READ: Could not read domain, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	serviceInfos, err := d.client.GetServiceInfos(serviceName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading domain",
+			"READ: Could not read service infos, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	setDomainFromAPI(data, domain, serviceInfos)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func setDomainFromAPI(data *CDCDomainDataSourceModel, domain api.Domain, serviceInfos api.ServiceInfos) {
+	data.NameServerType = types.StringValue(domain.NameServerType)
+	data.Offer = types.StringValue(domain.Offer)
+	data.State = types.StringValue(domain.State)
+	data.SuspensionState = types.StringValue(domain.SuspensionState)
+	data.TransferLockStatus = types.StringValue(domain.TransferLockStatus)
+	data.WhoisOwner = types.StringValue(domain.WhoisOwner)
+	data.LastUpdate = types.StringValue(domain.LastUpdate)
+	data.DNSSECSupported = types.BoolValue(domain.DNSSECSupported)
+	data.OwoSupported = types.BoolValue(domain.OwoSupported)
+	data.GlueRecordIPv6Supported = types.BoolValue(domain.GlueRecordIPv6Supported)
+	data.GlueRecordMultiIPSupported = types.BoolValue(domain.GlueRecordMultiIPSupported)
+	data.HostSupported = types.BoolValue(domain.HostSupported)
+
+	data.ParentService = nil
+	if domain.ParentService != nil {
+		data.ParentService = &CDCDomainParentServiceModel{
+			Name: types.StringValue(domain.ParentService.Name),
+			Type: types.StringValue(domain.ParentService.Type),
+		}
+	}
+
+	data.ServiceStatus = types.StringValue(serviceInfos.Status)
+	data.Creation = types.StringValue(serviceInfos.Creation)
+	data.Expiration = types.StringValue(serviceInfos.Expiration)
+	data.Expired = types.BoolValue(false)
+	if expiration, err := time.Parse("2006-01-02", serviceInfos.Expiration); err == nil && expiration.Before(time.Now()) {
+		data.Expired = types.BoolValue(true)
+	}
+
+	data.RenewMode = types.StringValue(renewMode(serviceInfos.Renew))
+	data.RenewPeriod = types.Int64PointerValue(serviceInfos.Renew.Period)
+	data.DeleteAtExpiration = types.BoolValue(serviceInfos.Renew.DeleteAtExpiration)
+
+	data.PossibleRenewPeriods = []types.Int64{}
+	for _, period := range serviceInfos.PossibleRenewPeriod {
+		data.PossibleRenewPeriods = append(data.PossibleRenewPeriods, types.Int64Value(period))
+	}
+
+	data.ContactAdmin = types.StringValue(serviceInfos.ContactAdmin)
+	data.ContactTech = types.StringValue(serviceInfos.ContactTech)
+	data.ContactBilling = types.StringValue(serviceInfos.ContactBilling)
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSetDomainFromAPI(t *testing.T) {
+	day := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	testCases := map[string]struct {
+		domain       api.Domain
+		serviceInfos api.ServiceInfos
+		prior        CDCDomainDataSourceModel
+		check        func(t *testing.T, data CDCDomainDataSourceModel)
+	}{
+		"active domain": {
+			domain: api.Domain{NameServerType: "external", TransferLockStatus: api.TransferLockLocked, DNSSECSupported: true},
+			serviceInfos: api.ServiceInfos{
+				Status:              "ok",
+				Expiration:          day(30),
+				Renew:               api.ServiceRenew{Automatic: true, Period: int64Pointer(12)},
+				PossibleRenewPeriod: []int64{12, 24},
+				ContactAdmin:        "ab1234-ovh",
+			},
+			check: func(t *testing.T, data CDCDomainDataSourceModel) {
+				if data.Expired.ValueBool() {
+					t.Errorf("expired = true for a domain expiring in 30 days")
+				}
+				if data.RenewMode.ValueString() != RenewModeAutomatic || data.RenewPeriod.ValueInt64() != 12 {
+					t.Errorf("renew_mode, renew_period = %s, %s, want %s, 12", data.RenewMode, data.RenewPeriod, RenewModeAutomatic)
+				}
+				if !reflect.DeepEqual(data.PossibleRenewPeriods, []types.Int64{types.Int64Value(12), types.Int64Value(24)}) {
+					t.Errorf("possible_renew_periods = %v, want [12 24]", data.PossibleRenewPeriods)
+				}
+				if data.TransferLockStatus.ValueString() != api.TransferLockLocked || !data.DNSSECSupported.ValueBool() || data.ContactAdmin.ValueString() != "ab1234-ovh" {
+					t.Errorf("domain attributes not copied: %+v", data)
+				}
+			},
+		},
+		"expired domain": {
+			serviceInfos: api.ServiceInfos{Expiration: day(-1), Renew: api.ServiceRenew{ManualPayment: true}},
+			check: func(t *testing.T, data CDCDomainDataSourceModel) {
+				if !data.Expired.ValueBool() {
+					t.Errorf("expired = false for a domain expired yesterday")
+				}
+				if data.RenewMode.ValueString() != RenewModeManual || !data.RenewPeriod.IsNull() {
+					t.Errorf("renew_mode, renew_period = %s, %s, want %s, null", data.RenewMode, data.RenewPeriod, RenewModeManual)
+				}
+				if data.PossibleRenewPeriods == nil || len(data.PossibleRenewPeriods) != 0 {
+					t.Errorf("possible_renew_periods = %#v, want an empty list", data.PossibleRenewPeriods)
+				}
+			},
+		},
+		"unparsable expiration": {
+			serviceInfos: api.ServiceInfos{Expiration: "soon"},
+			check: func(t *testing.T, data CDCDomainDataSourceModel) {
+				if data.Expired.ValueBool() {
+					t.Errorf("expired = true for an unparsable expiration")
+				}
+			},
+		},
+		"parent service": {
+			domain: api.Domain{ParentService: &api.DomainParentService{Name: "example.com", Type: "/hosting/web"}},
+			check: func(t *testing.T, data CDCDomainDataSourceModel) {
+				want := &CDCDomainParentServiceModel{Name: types.StringValue("example.com"), Type: types.StringValue("/hosting/web")}
+				if !reflect.DeepEqual(data.ParentService, want) {
+					t.Errorf("parent_service = %+v, want %+v", data.ParentService, want)
+				}
+			},
+		},
+		"parent service removed": {
+			prior: CDCDomainDataSourceModel{ParentService: &CDCDomainParentServiceModel{Name: types.StringValue("example.com"), Type: types.StringValue("/hosting/web")}},
+			check: func(t *testing.T, data CDCDomainDataSourceModel) {
+				if data.ParentService != nil {
+					t.Errorf("parent_service = %+v, want null", data.ParentService)
+				}
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := testCase.prior
+			setDomainFromAPI(&data, testCase.domain, testCase.serviceInfos)
+			testCase.check(t, data)
+		})
+	}
+}
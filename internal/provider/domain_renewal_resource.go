@@ -0,0 +1,479 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CDCDomainRenewalResource{}
+var _ resource.ResourceWithImportState = &CDCDomainRenewalResource{}
+var _ resource.ResourceWithModifyPlan = &CDCDomainRenewalResource{}
+var _ resource.ResourceWithValidateConfig = &CDCDomainRenewalResource{}
+
+const (
+	RenewModeAutomatic string = "automatic"
+	RenewModeManual    string = "manual"
+
+	defaultExpirationWarningDays int64 = 30
+)
+
+func NewCDCDomainRenewalResource() resource.Resource {
+	return &CDCDomainRenewalResource{}
+}
+
+type CDCDomainRenewalResource struct {
+	client *api.APIClient
+}
+
+type CDCDomainRenewalResourceModel struct {
+	ID                    types.String  `tfsdk:"id"`
+	ServiceName           HostnameValue `tfsdk:"service_name"`
+	RenewMode             types.String  `tfsdk:"renew_mode"`
+	Period                types.Int64   `tfsdk:"period"`
+	DeleteAtExpiration    types.Bool    `tfsdk:"delete_at_expiration"`
+	ExpirationWarningDays types.Int64   `tfsdk:"expiration_warning_days"`
+	Expiration            types.String  `tfsdk:"expiration"`
+	Creation              types.String  `tfsdk:"creation"`
+}
+
+func (r *CDCDomainRenewalResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_renewal"
+}
+
+func (r *CDCDomainRenewalResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "OVH domain renewal resource. Manages the renewal settings of a domain, destroying it leaves them as they are.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					hostnameRequiresReplace(),
+				},
+			},
+			"renew_mode": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(RenewModeAutomatic),
+				Description: "Renewal mode: automatic or manual. Defaults to automatic.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(RenewModeAutomatic, RenewModeManual),
+				},
+			},
+			"period": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Renewal period in months, one of the periods offered by OVH for the domain. Defaults to the current period.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 120),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"delete_at_expiration": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Delete the domain when it expires. Requires renew_mode manual. Defaults to false.",
+			},
+			"expiration_warning_days": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(defaultExpirationWarningDays),
+				Description: "Warn when planning if the domain expires within this number of days, 0 disables the warning. Defaults to 30.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"expiration": schema.StringAttribute{
+				Computed:    true,
+				Description: "Expiration date of the domain",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"creation": schema.StringAttribute{
+				Computed:    true,
+				Description: "Creation date of the domain",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *CDCDomainRenewalResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *CDCDomainRenewalResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ServiceName.IsNull() && !data.ServiceName.IsUnknown() {
+		_, diags := parseServiceName(data.ServiceName)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	// renew_mode defaults to automatic when it is not configured.
+	if data.DeleteAtExpiration.ValueBool() && !data.RenewMode.IsUnknown() && data.RenewMode.ValueString() != RenewModeManual {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("delete_at_expiration"),
+			"Conflicting renewal settings",
+			"delete_at_expiration requires renew_mode manual, an automatically renewed domain does not expire",
+		)
+	}
+}
+
+func (r *CDCDomainRenewalResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CDCDomainRenewalResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *CDCDomainRenewalResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// Nothing to check on destroy, or before the values are known.
+	if plan == nil || plan.ServiceName.IsUnknown() || plan.ExpirationWarningDays.IsUnknown() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// State was refreshed just before planning, its expiration is current.
+	if state != nil && !renewalChanged(plan, state) {
+		resp.Diagnostics.Append(expirationDiagnostics(serviceName, state.Expiration.ValueString(), plan.ExpirationWarningDays.ValueInt64())...)
+		return
+	}
+
+	serviceInfos, err := r.client.GetServiceInfos(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading service infos",
+			"Could not read renewal settings, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(expirationDiagnostics(serviceName, serviceInfos.Expiration, plan.ExpirationWarningDays.ValueInt64())...)
+
+	if plan.Period.IsUnknown() {
+		plan.Period = types.Int64PointerValue(serviceInfos.Renew.Period)
+	}
+
+	if state == nil {
+		plan.Expiration = types.StringValue(serviceInfos.Expiration)
+		plan.Creation = types.StringValue(serviceInfos.Creation)
+	}
+
+	if !plan.Period.IsNull() && len(serviceInfos.PossibleRenewPeriod) > 0 && !containsInt64(serviceInfos.PossibleRenewPeriod, plan.Period.ValueInt64()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("period"),
+			"Renewal period not offered",
+			fmt.Sprintf("OVH renews %s for one of these periods, in months: %v", serviceName, serviceInfos.PossibleRenewPeriod),
+		)
+	}
+
+	if plan.DeleteAtExpiration.ValueBool() && !serviceInfos.CanDeleteAtExpiration {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("delete_at_expiration"),
+			"Deletion at expiration not available",
+			fmt.Sprintf("OVH does not allow deleting %s at expiration", serviceName),
+		)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *CDCDomainRenewalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CDCDomainRenewalResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.applyRenewal(serviceName, data); err != nil {
+		resp.Diagnostics.AddError(
+			"Error setting renewal",
+			"CREATE: "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainRenewalResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CDCDomainRenewalResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceInfos, err := r.client.GetServiceInfos(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Domain not found",
+			fmt.Sprintf("READ: Domain %s is not in the OVH account anymore. Removing its renewal settings from state", serviceName),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading renewal",
+			"READ: Could not read service infos, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	setRenewalFromServiceInfos(data, serviceInfos)
+
+	// Not set after an import.
+	if data.ExpirationWarningDays.IsNull() {
+		data.ExpirationWarningDays = types.Int64Value(defaultExpirationWarningDays)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainRenewalResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state *CDCDomainRenewalResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if renewalChanged(plan, state) {
+		if err := r.applyRenewal(serviceName, plan); err != nil {
+			resp.Diagnostics.AddError(
+				"Error setting renewal",
+				"UPDATE: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state. Changing the renewal of a
+// domain because its resource was destroyed could let it expire.
+func (r *CDCDomainRenewalResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *CDCDomainRenewalResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	serviceName, err := api.ParseDomainName(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected a domain name: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), serviceName.String())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), req.ID)...)
+}
+
+// applyRenewal sends the renewal settings of data, keeping the settings it
+// does not manage, then checks that OVH reports them. data is left as planned.
+func (r *CDCDomainRenewalResource) applyRenewal(serviceName api.DomainName, data *CDCDomainRenewalResourceModel) error {
+	serviceInfos, err := r.client.GetServiceInfos(serviceName)
+	if err != nil {
+		return fmt.Errorf("could not read service infos, unexpected error: %w", err)
+	}
+
+	renew := serviceInfos.Renew
+	renew.Automatic = data.RenewMode.ValueString() == RenewModeAutomatic
+	renew.ManualPayment = !renew.Automatic
+	renew.DeleteAtExpiration = data.DeleteAtExpiration.ValueBool()
+	if !data.Period.IsNull() && !data.Period.IsUnknown() {
+		renew.Period = data.Period.ValueInt64Pointer()
+	}
+
+	if err := r.client.UpdateServiceRenew(serviceName, renew); err != nil {
+		return fmt.Errorf("could not update renewal settings, unexpected error: %w", err)
+	}
+
+	serviceInfos, err = r.client.GetServiceInfos(serviceName)
+	if err != nil {
+		return fmt.Errorf("renewal settings were updated but could not be read back: %w", err)
+	}
+
+	if mismatches := renewalMismatches(data, serviceInfos); len(mismatches) > 0 {
+		return fmt.Errorf("renewal settings were updated but OVH reports other values for %v, the next apply will set them again", mismatches)
+	}
+
+	return nil
+}
+
+// renewalMismatches returns the attributes whose planned value differs from the
+// renewal settings reported by OVH.
+func renewalMismatches(data *CDCDomainRenewalResourceModel, serviceInfos api.ServiceInfos) []string {
+	mismatches := []string{}
+
+	if data.RenewMode.ValueString() != renewMode(serviceInfos.Renew) {
+		mismatches = append(mismatches, "renew_mode")
+	}
+
+	if !data.Period.IsNull() && !data.Period.IsUnknown() && !data.Period.Equal(types.Int64PointerValue(serviceInfos.Renew.Period)) {
+		mismatches = append(mismatches, "period")
+	}
+
+	if data.DeleteAtExpiration.ValueBool() != serviceInfos.Renew.DeleteAtExpiration {
+		mismatches = append(mismatches, "delete_at_expiration")
+	}
+
+	return mismatches
+}
+
+func setRenewalFromServiceInfos(data *CDCDomainRenewalResourceModel, serviceInfos api.ServiceInfos) {
+	data.RenewMode = types.StringValue(renewMode(serviceInfos.Renew))
+	data.Period = types.Int64PointerValue(serviceInfos.Renew.Period)
+	data.DeleteAtExpiration = types.BoolValue(serviceInfos.Renew.DeleteAtExpiration)
+	data.Expiration = types.StringValue(serviceInfos.Expiration)
+	data.Creation = types.StringValue(serviceInfos.Creation)
+}
+
+// renewMode returns automatic for domains renewed and paid automatically.
+func renewMode(renew api.ServiceRenew) string {
+	if renew.Automatic && !renew.ManualPayment {
+		return RenewModeAutomatic
+	}
+	return RenewModeManual
+}
+
+func renewalChanged(plan, state *CDCDomainRenewalResourceModel) bool {
+	return !plan.RenewMode.Equal(state.RenewMode) ||
+		!plan.DeleteAtExpiration.Equal(state.DeleteAtExpiration) ||
+		(!plan.Period.IsUnknown() && !plan.Period.Equal(state.Period))
+}
+
+// expirationDiagnostics warns when the domain expires within days.
+func expirationDiagnostics(serviceName api.DomainName, expiration string, days int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if days == 0 || expiration == "" {
+		return diags
+	}
+
+	expiresAt, err := time.Parse("2006-01-02", expiration)
+	if err != nil {
+		expiresAt, err = time.Parse(time.RFC3339, expiration)
+	}
+	if err != nil {
+		return diags
+	}
+
+	remaining := time.Until(expiresAt)
+	if remaining < 0 {
+		diags.AddWarning(
+			"Domain expired",
+			fmt.Sprintf("%s expired on %s. Renew it in the OVH Panel before it is deleted", serviceName, expiration),
+		)
+		return diags
+	}
+
+	if remaining < time.Duration(days)*24*time.Hour {
+		diags.AddWarning(
+			"Domain expires soon",
+			fmt.Sprintf("%s expires on %s, in %d days. Check its renewal and payment method in the OVH Panel", serviceName, expiration, int64(math.Ceil(remaining.Hours()/24))),
+		)
+	}
+
+	return diags
+}
+
+func containsInt64(values []int64, value int64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
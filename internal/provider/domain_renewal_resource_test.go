@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func int64Pointer(value int64) *int64 {
+	return &value
+}
+
+func TestRenewMode(t *testing.T) {
+	testCases := map[string]struct {
+		renew api.ServiceRenew
+		want  string
+	}{
+		"automatic":              {renew: api.ServiceRenew{Automatic: true}, want: RenewModeAutomatic},
+		"automatic paid by hand": {renew: api.ServiceRenew{Automatic: true, ManualPayment: true}, want: RenewModeManual},
+		"manual":                 {renew: api.ServiceRenew{ManualPayment: true}, want: RenewModeManual},
+		"neither":                {renew: api.ServiceRenew{}, want: RenewModeManual},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := renewMode(testCase.renew); got != testCase.want {
+				t.Errorf("renewMode(%+v) = %s, want %s", testCase.renew, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSetRenewalFromServiceInfos(t *testing.T) {
+	testCases := map[string]struct {
+		serviceInfos api.ServiceInfos
+		want         CDCDomainRenewalResourceModel
+	}{
+		"automatic with period": {
+			serviceInfos: api.ServiceInfos{
+				Creation:   "2020-01-01",
+				Expiration: "2030-01-01",
+				Renew:      api.ServiceRenew{Automatic: true, Period: int64Pointer(12)},
+			},
+			want: CDCDomainRenewalResourceModel{
+				RenewMode:          types.StringValue(RenewModeAutomatic),
+				Period:             types.Int64Value(12),
+				DeleteAtExpiration: types.BoolValue(false),
+				Creation:           types.StringValue("2020-01-01"),
+				Expiration:         types.StringValue("2030-01-01"),
+			},
+		},
+		"manual deleted at expiration without period": {
+			serviceInfos: api.ServiceInfos{
+				Creation:   "2020-01-01",
+				Expiration: "2030-01-01",
+				Renew:      api.ServiceRenew{ManualPayment: true, DeleteAtExpiration: true},
+			},
+			want: CDCDomainRenewalResourceModel{
+				RenewMode:          types.StringValue(RenewModeManual),
+				Period:             types.Int64Null(),
+				DeleteAtExpiration: types.BoolValue(true),
+				Creation:           types.StringValue("2020-01-01"),
+				Expiration:         types.StringValue("2030-01-01"),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := &CDCDomainRenewalResourceModel{ID: types.StringValue("example.com"), ExpirationWarningDays: types.Int64Value(30)}
+			setRenewalFromServiceInfos(data, testCase.serviceInfos)
+
+			testCase.want.ID = data.ID
+			testCase.want.ExpirationWarningDays = data.ExpirationWarningDays
+			if *data != testCase.want {
+				t.Errorf("setRenewalFromServiceInfos(%+v) = %+v, want %+v", testCase.serviceInfos, *data, testCase.want)
+			}
+		})
+	}
+}
+
+func TestRenewalMismatches(t *testing.T) {
+	planned := func(mode string, period types.Int64, deleteAtExpiration bool) *CDCDomainRenewalResourceModel {
+		return &CDCDomainRenewalResourceModel{
+			RenewMode:          types.StringValue(mode),
+			Period:             period,
+			DeleteAtExpiration: types.BoolValue(deleteAtExpiration),
+		}
+	}
+
+	testCases := map[string]struct {
+		data  *CDCDomainRenewalResourceModel
+		renew api.ServiceRenew
+		want  []string
+	}{
+		"as planned": {
+			data:  planned(RenewModeAutomatic, types.Int64Value(12), false),
+			renew: api.ServiceRenew{Automatic: true, Period: int64Pointer(12)},
+		},
+		"manual as planned": {
+			data:  planned(RenewModeManual, types.Int64Null(), true),
+			renew: api.ServiceRenew{ManualPayment: true, DeleteAtExpiration: true},
+		},
+		"payment still manual": {
+			data:  planned(RenewModeAutomatic, types.Int64Value(12), false),
+			renew: api.ServiceRenew{Automatic: true, ManualPayment: true, Period: int64Pointer(12)},
+			want:  []string{"renew_mode"},
+		},
+		"other period": {
+			data:  planned(RenewModeAutomatic, types.Int64Value(24), false),
+			renew: api.ServiceRenew{Automatic: true, Period: int64Pointer(12)},
+			want:  []string{"period"},
+		},
+		"unknown period not compared": {
+			data:  planned(RenewModeAutomatic, types.Int64Unknown(), false),
+			renew: api.ServiceRenew{Automatic: true, Period: int64Pointer(12)},
+		},
+		"everything differs": {
+			data:  planned(RenewModeManual, types.Int64Value(24), true),
+			renew: api.ServiceRenew{Automatic: true, Period: int64Pointer(12)},
+			want:  []string{"renew_mode", "period", "delete_at_expiration"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := renewalMismatches(testCase.data, api.ServiceInfos{Renew: testCase.renew})
+
+			if strings.Join(got, ", ") != strings.Join(testCase.want, ", ") {
+				t.Errorf("renewalMismatches(%+v) = %v, want %v", testCase.renew, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestExpirationDiagnostics(t *testing.T) {
+	day := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	testCases := map[string]struct {
+		expiration  string
+		days        int64
+		wantSummary string
+	}{
+		"far away":         {expiration: day(90), days: 30},
+		"soon":             {expiration: day(10), days: 30, wantSummary: "Domain expires soon"},
+		"expired":          {expiration: day(-3), days: 30, wantSummary: "Domain expired"},
+		"RFC 3339 date":    {expiration: time.Now().Add(48 * time.Hour).Format(time.RFC3339), days: 30, wantSummary: "Domain expires soon"},
+		"warning disabled": {expiration: day(10), days: 0},
+		"no expiration":    {expiration: "", days: 30},
+		"unparsable date":  {expiration: "soon", days: 30},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := expirationDiagnostics(api.DomainName("example.com"), testCase.expiration, testCase.days)
+
+			if testCase.wantSummary == "" {
+				if len(diags) != 0 {
+					t.Errorf("got diagnostics %v, want none", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Summary() != testCase.wantSummary || diags.HasError() {
+				t.Errorf("got diagnostics %v, want one %q warning", diags, testCase.wantSummary)
+			}
+		})
+	}
+}
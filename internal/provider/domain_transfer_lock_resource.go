@@ -0,0 +1,339 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CDCDomainTransferLockResource{}
+var _ resource.ResourceWithImportState = &CDCDomainTransferLockResource{}
+var _ resource.ResourceWithModifyPlan = &CDCDomainTransferLockResource{}
+var _ resource.ResourceWithValidateConfig = &CDCDomainTransferLockResource{}
+
+// transferLockTimeout bounds the wait for the registry to apply a transfer
+// lock change. A lock still changing is waited for again by the next apply.
+const transferLockTimeout time.Duration = time.Hour
+
+func NewCDCDomainTransferLockResource() resource.Resource {
+	return &CDCDomainTransferLockResource{}
+}
+
+type CDCDomainTransferLockResource struct {
+	client *api.APIClient
+}
+
+type CDCDomainTransferLockResourceModel struct {
+	ID          types.String  `tfsdk:"id"`
+	ServiceName HostnameValue `tfsdk:"service_name"`
+	Status      types.String  `tfsdk:"status"`
+}
+
+func (r *CDCDomainTransferLockResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_transfer_lock"
+}
+
+func (r *CDCDomainTransferLockResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "OVH domain transfer lock resource. Destroying it leaves the transfer lock as it is.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					hostnameRequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(api.TransferLockLocked),
+				Description: "Transfer lock status: locked or unlocked. Defaults to locked. Applies wait up to 1h for the registry to apply a change.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(api.TransferLockLocked, api.TransferLockUnlocked),
+				},
+			},
+		},
+	}
+}
+
+func (r *CDCDomainTransferLockResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *CDCDomainTransferLockResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ServiceName.IsNull() && !data.ServiceName.IsUnknown() {
+		_, diags := parseServiceName(data.ServiceName)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *CDCDomainTransferLockResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CDCDomainTransferLockResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *CDCDomainTransferLockResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// Nothing to check on destroy, or before the values are known.
+	if plan == nil || plan.ServiceName.IsUnknown() || plan.Status.IsUnknown() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state != nil && plan.Status.Equal(state.Status) {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading domain",
+			"Could not read domain state, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(domainStateDiagnostics(domain, true)...)
+
+	if domain.TransferLockStatus == api.TransferLockUnavailable {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("status"),
+			"Transfer lock not available",
+			fmt.Sprintf("The registry of %s does not support a transfer lock", serviceName),
+		)
+	}
+}
+
+func (r *CDCDomainTransferLockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CDCDomainTransferLockResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.setTransferLock(ctx, serviceName, data.Status.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Error setting transfer lock",
+			"CREATE: "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainTransferLockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CDCDomainTransferLockResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Domain not found",
+			fmt.Sprintf("READ: Domain %s is not in the OVH account anymore. Removing its transfer lock from state", serviceName),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading transfer lock",
+			"READ: Could not read domain, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	data.Status = types.StringValue(transferLockTarget(domain.TransferLockStatus))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainTransferLockResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *CDCDomainTransferLockResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.setTransferLock(ctx, serviceName, plan.Status.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Error setting transfer lock",
+			"UPDATE: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state. Unlocking a domain because its
+// resource was destroyed would open it to transfers.
+func (r *CDCDomainTransferLockResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *CDCDomainTransferLockResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	serviceName, err := api.ParseDomainName(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected a domain name: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), serviceName.String())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), req.ID)...)
+}
+
+// setTransferLock asks for status unless the domain already has it or is
+// moving to it, then waits until the registry applied it.
+func (r *CDCDomainTransferLockResource) setTransferLock(ctx context.Context, serviceName api.DomainName, status string) error {
+	domain, err := r.client.GetDomain(serviceName)
+	if err != nil {
+		return fmt.Errorf("could not read domain, unexpected error: %w", err)
+	}
+
+	if domain.TransferLockStatus == status {
+		return nil
+	}
+
+	if transferLockTarget(domain.TransferLockStatus) != status {
+		if err := r.client.SetTransferLockStatus(serviceName, status); err != nil {
+			return fmt.Errorf("could not set transfer lock to %s, unexpected error: %w", status, err)
+		}
+	}
+
+	return waitForTransferLock(ctx, r.client, serviceName, status)
+}
+
+// waitForTransferLock polls the domain until its transfer lock has status, for
+// at most transferLockTimeout. The registry refused the change when the lock
+// goes back to its previous status after a transitional one.
+func waitForTransferLock(ctx context.Context, client *api.APIClient, serviceName api.DomainName, status string) error {
+	transitional := false
+	deadline := time.After(transferLockTimeout)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for transfer lock of %s to be %s: %w", serviceName, status, ctx.Err())
+		case <-deadline:
+			return fmt.Errorf("transfer lock of %s is not %s after %s, the next apply will wait for it again", serviceName, status, transferLockTimeout)
+		case <-time.After(api.CHECK_STATUS_WAIT_TIME):
+		}
+
+		domain, err := client.GetDomain(serviceName)
+		if err != nil {
+			return fmt.Errorf("could not read domain, unexpected error: %w", err)
+		}
+
+		switch domain.TransferLockStatus {
+		case status:
+			return nil
+		case api.TransferLockLocking, api.TransferLockUnlocking:
+			transitional = true
+		case api.TransferLockUnavailable:
+			return fmt.Errorf("transfer lock of %s is unavailable", serviceName)
+		default:
+			if transitional {
+				return fmt.Errorf("transfer lock of %s went back to %s, check OVH Panel", serviceName, domain.TransferLockStatus)
+			}
+		}
+	}
+}
+
+// transferLockTarget returns the status a transitional transfer lock status
+// leads to, so a lock being removed shows as drift right away.
+func transferLockTarget(status string) string {
+	switch status {
+	case api.TransferLockLocking:
+		return api.TransferLockLocked
+	case api.TransferLockUnlocking:
+		return api.TransferLockUnlocked
+	default:
+		return status
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+)
+
+func TestTransferLockTarget(t *testing.T) {
+	testCases := map[string]struct {
+		status string
+		want   string
+	}{
+		"locking":     {status: api.TransferLockLocking, want: api.TransferLockLocked},
+		"unlocking":   {status: api.TransferLockUnlocking, want: api.TransferLockUnlocked},
+		"locked":      {status: api.TransferLockLocked, want: api.TransferLockLocked},
+		"unlocked":    {status: api.TransferLockUnlocked, want: api.TransferLockUnlocked},
+		"unavailable": {status: api.TransferLockUnavailable, want: api.TransferLockUnavailable},
+		"unknown":     {status: "pending", want: "pending"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := transferLockTarget(testCase.status); got != testCase.want {
+				t.Errorf("transferLockTarget(%q) = %q, want %q", testCase.status, got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CDCDomainWhoisObfuscationResource{}
+var _ resource.ResourceWithImportState = &CDCDomainWhoisObfuscationResource{}
+var _ resource.ResourceWithModifyPlan = &CDCDomainWhoisObfuscationResource{}
+var _ resource.ResourceWithValidateConfig = &CDCDomainWhoisObfuscationResource{}
+
+func NewCDCDomainWhoisObfuscationResource() resource.Resource {
+	return &CDCDomainWhoisObfuscationResource{}
+}
+
+type CDCDomainWhoisObfuscationResource struct {
+	client *api.APIClient
+}
+
+type CDCDomainWhoisObfuscationResourceModel struct {
+	ID          types.String  `tfsdk:"id"`
+	ServiceName HostnameValue `tfsdk:"service_name"`
+	Email       types.Bool    `tfsdk:"email"`
+	Phone       types.Bool    `tfsdk:"phone"`
+	Address     types.Bool    `tfsdk:"address"`
+	Name        types.Bool    `tfsdk:"name"`
+}
+
+// fields returns the planned value of each WHOIS field, by OVH field name.
+func (m *CDCDomainWhoisObfuscationResourceModel) fields() map[string]types.Bool {
+	return map[string]types.Bool{
+		api.OwoFieldEmail:   m.Email,
+		api.OwoFieldPhone:   m.Phone,
+		api.OwoFieldAddress: m.Address,
+		api.OwoFieldName:    m.Name,
+	}
+}
+
+func (r *CDCDomainWhoisObfuscationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_whois_obfuscation"
+}
+
+func (r *CDCDomainWhoisObfuscationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	fieldAttribute := func(description string) schema.BoolAttribute {
+		return schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(true),
+			Description: description,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "OVH domain WHOIS obfuscation (OWO) resource. Hides contact fields from the WHOIS of a domain, destroying it leaves them as they are.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					hostnameRequiresReplace(),
+				},
+			},
+			"email":   fieldAttribute("Hide the email address of the contacts. Defaults to true."),
+			"phone":   fieldAttribute("Hide the phone number of the contacts. Defaults to true."),
+			"address": fieldAttribute("Hide the postal address of the contacts. Defaults to true."),
+			"name":    fieldAttribute("Hide the name of the contacts. Defaults to true."),
+		},
+	}
+}
+
+func (r *CDCDomainWhoisObfuscationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *CDCDomainWhoisObfuscationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ServiceName.IsNull() && !data.ServiceName.IsUnknown() {
+		_, diags := parseServiceName(data.ServiceName)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *CDCDomainWhoisObfuscationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CDCDomainWhoisObfuscationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *CDCDomainWhoisObfuscationResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// Nothing to check on destroy, or before the values are known.
+	if plan == nil || plan.ServiceName.IsUnknown() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state != nil && !obfuscationChanged(plan, state) {
+		return
+	}
+
+	obfuscated := false
+	for _, value := range plan.fields() {
+		if value.ValueBool() {
+			obfuscated = true
+		}
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading domain",
+			"Could not read domain state, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(domainStateDiagnostics(domain, true)...)
+
+	if obfuscated && !domain.OwoSupported {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"WHOIS obfuscation not supported",
+			fmt.Sprintf("OVH cannot hide WHOIS fields of %s, its registry does not allow it. Set every field to false or remove the resource", serviceName),
+		)
+	}
+}
+
+func (r *CDCDomainWhoisObfuscationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CDCDomainWhoisObfuscationResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.applyObfuscation(serviceName, data); err != nil {
+		resp.Diagnostics.AddError(
+			"Error setting WHOIS obfuscation",
+			"CREATE: "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainWhoisObfuscationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CDCDomainWhoisObfuscationResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fields, err := r.client.GetObfuscatedFields(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Domain not found",
+			fmt.Sprintf("READ: Domain %s is not in the OVH account anymore. Removing its WHOIS obfuscation from state", serviceName),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading WHOIS obfuscation",
+			"READ: Could not read obfuscated fields, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	setObfuscatedFields(data, fields)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDomainWhoisObfuscationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *CDCDomainWhoisObfuscationResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.applyObfuscation(serviceName, plan); err != nil {
+		resp.Diagnostics.AddError(
+			"Error setting WHOIS obfuscation",
+			"UPDATE: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state. Showing contact details in the
+// WHOIS because the resource was destroyed would leak personal data.
+func (r *CDCDomainWhoisObfuscationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *CDCDomainWhoisObfuscationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	serviceName, err := api.ParseDomainName(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected a domain name: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), serviceName.String())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), req.ID)...)
+}
+
+// applyObfuscation hides and shows the WHOIS fields that differ from data,
+// then checks that OVH reports the planned fields. data is left as planned.
+func (r *CDCDomainWhoisObfuscationResource) applyObfuscation(serviceName api.DomainName, data *CDCDomainWhoisObfuscationResourceModel) error {
+	current, err := r.client.GetObfuscatedFields(serviceName)
+	if err != nil {
+		return fmt.Errorf("could not read obfuscated fields, unexpected error: %w", err)
+	}
+
+	hide := []string{}
+	for field, value := range data.fields() {
+		obfuscated := containsString(current, field)
+
+		if value.ValueBool() && !obfuscated {
+			hide = append(hide, field)
+		}
+
+		if !value.ValueBool() && obfuscated {
+			if err := r.client.UnobfuscateField(serviceName, field); err != nil {
+				return fmt.Errorf("could not show %s in WHOIS, unexpected error: %w", field, err)
+			}
+		}
+	}
+
+	if len(hide) > 0 {
+		if err := r.client.ObfuscateFields(serviceName, hide); err != nil {
+			return fmt.Errorf("could not hide %v from WHOIS, unexpected error: %w", hide, err)
+		}
+	}
+
+	fields, err := r.client.GetObfuscatedFields(serviceName)
+	if err != nil {
+		return fmt.Errorf("obfuscated fields were updated but could not be read back: %w", err)
+	}
+
+	if mismatches := obfuscationMismatches(data, fields); len(mismatches) > 0 {
+		return fmt.Errorf("obfuscated fields were updated but OVH reports other values for %v, the next apply will set them again", mismatches)
+	}
+
+	return nil
+}
+
+func setObfuscatedFields(data *CDCDomainWhoisObfuscationResourceModel, fields []string) {
+	data.Email = types.BoolValue(containsString(fields, api.OwoFieldEmail))
+	data.Phone = types.BoolValue(containsString(fields, api.OwoFieldPhone))
+	data.Address = types.BoolValue(containsString(fields, api.OwoFieldAddress))
+	data.Name = types.BoolValue(containsString(fields, api.OwoFieldName))
+}
+
+// obfuscationMismatches returns the OVH names of the fields whose planned value
+// differs from the obfuscated fields reported by OVH.
+func obfuscationMismatches(data *CDCDomainWhoisObfuscationResourceModel, fields []string) []string {
+	mismatches := []string{}
+	for field, value := range data.fields() {
+		if value.ValueBool() != containsString(fields, field) {
+			mismatches = append(mismatches, field)
+		}
+	}
+	sort.Strings(mismatches)
+
+	return mismatches
+}
+
+func obfuscationChanged(plan, state *CDCDomainWhoisObfuscationResourceModel) bool {
+	stateFields := state.fields()
+	for field, value := range plan.fields() {
+		if !value.Equal(stateFields[field]) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func obfuscationModel(email, phone, address, name bool) *CDCDomainWhoisObfuscationResourceModel {
+	return &CDCDomainWhoisObfuscationResourceModel{
+		Email:   types.BoolValue(email),
+		Phone:   types.BoolValue(phone),
+		Address: types.BoolValue(address),
+		Name:    types.BoolValue(name),
+	}
+}
+
+func TestSetObfuscatedFields(t *testing.T) {
+	testCases := map[string]struct {
+		fields []string
+		want   *CDCDomainWhoisObfuscationResourceModel
+	}{
+		"all hidden":   {fields: []string{api.OwoFieldAddress, api.OwoFieldEmail, api.OwoFieldName, api.OwoFieldPhone}, want: obfuscationModel(true, true, true, true)},
+		"none hidden":  {fields: []string{}, want: obfuscationModel(false, false, false, false)},
+		"some hidden":  {fields: []string{api.OwoFieldPhone, api.OwoFieldEmail}, want: obfuscationModel(true, true, false, false)},
+		"unknown name": {fields: []string{"fax", api.OwoFieldName}, want: obfuscationModel(false, false, false, true)},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := &CDCDomainWhoisObfuscationResourceModel{}
+			setObfuscatedFields(data, testCase.fields)
+
+			if obfuscationChanged(data, testCase.want) {
+				t.Errorf("setObfuscatedFields(%v) = %+v, want %+v", testCase.fields, data, testCase.want)
+			}
+		})
+	}
+}
+
+func TestObfuscationMismatches(t *testing.T) {
+	testCases := map[string]struct {
+		data   *CDCDomainWhoisObfuscationResourceModel
+		fields []string
+		want   []string
+	}{
+		"as planned":         {data: obfuscationModel(true, false, true, false), fields: []string{api.OwoFieldAddress, api.OwoFieldEmail}},
+		"nothing hidden":     {data: obfuscationModel(false, false, false, false), fields: []string{}},
+		"not hidden yet":     {data: obfuscationModel(true, true, true, true), fields: []string{api.OwoFieldEmail, api.OwoFieldPhone}, want: []string{api.OwoFieldAddress, api.OwoFieldName}},
+		"still hidden":       {data: obfuscationModel(false, true, true, true), fields: []string{api.OwoFieldEmail, api.OwoFieldPhone, api.OwoFieldAddress, api.OwoFieldName}, want: []string{api.OwoFieldEmail}},
+		"hidden and visible": {data: obfuscationModel(true, false, false, false), fields: []string{api.OwoFieldPhone}, want: []string{api.OwoFieldEmail, api.OwoFieldPhone}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := obfuscationMismatches(testCase.data, testCase.fields)
+
+			if strings.Join(got, ", ") != strings.Join(testCase.want, ", ") {
+				t.Errorf("obfuscationMismatches(%v) = %v, want %v", testCase.fields, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestObfuscationChanged(t *testing.T) {
+	testCases := map[string]struct {
+		plan  *CDCDomainWhoisObfuscationResourceModel
+		state *CDCDomainWhoisObfuscationResourceModel
+		want  bool
+	}{
+		"same":            {plan: obfuscationModel(true, true, true, true), state: obfuscationModel(true, true, true, true), want: false},
+		"one field":       {plan: obfuscationModel(true, true, true, false), state: obfuscationModel(true, true, true, true), want: true},
+		"all fields":      {plan: obfuscationModel(false, false, false, false), state: obfuscationModel(true, true, true, true), want: true},
+		"unknown in plan": {plan: &CDCDomainWhoisObfuscationResourceModel{Email: types.BoolUnknown(), Phone: types.BoolValue(true), Address: types.BoolValue(true), Name: types.BoolValue(true)}, state: obfuscationModel(true, true, true, true), want: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := obfuscationChanged(testCase.plan, testCase.state); got != testCase.want {
+				t.Errorf("obfuscationChanged = %t, want %t", got, testCase.want)
+			}
+		})
+	}
+}
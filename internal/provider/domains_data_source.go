@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CDCDomainsDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &CDCDomainsDataSource{}
+
+func NewCDCDomainsDataSource() datasource.DataSource {
+	return &CDCDomainsDataSource{}
+}
+
+type CDCDomainsDataSource struct {
+	client *api.APIClient
+}
+
+type CDCDomainsDataSourceModel struct {
+	WhoisOwner        types.String   `tfsdk:"whois_owner"`
+	NameRegex         types.String   `tfsdk:"name_regex"`
+	TLDs              []types.String `tfsdk:"tlds"`
+	NameServerType    types.String   `tfsdk:"name_server_type"`
+	ExpiresWithinDays types.Int64    `tfsdk:"expires_within_days"`
+	Names             []types.String `tfsdk:"names"`
+}
+
+func (d *CDCDomainsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domains"
+}
+
+func (d *CDCDomainsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "OVH domains data source. Lists the domains of the account, optionally filtered.",
+
+		Attributes: map[string]schema.Attribute{
+			"whois_owner": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list domains owned by this contact",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list domains matching this regular expression. Internationalised domains are matched in punycode, eg: xn--bcher-kva.example",
+			},
+			"tlds": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only list domains under one of these TLDs, eg: [\"com\", \"co.uk\"]. Internationalised TLDs may be given in Unicode or punycode",
+			},
+			"name_server_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list domains with this name server type: hosted or external",
+				Validators: []validator.String{
+					stringvalidator.OneOf(api.NSHosted, api.NSExternal),
+				},
+			},
+			"expires_within_days": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Only list domains expiring within this number of days, or already expired",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Sorted names of the matching domains",
+			},
+		},
+	}
+}
+
+func (d *CDCDomainsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data *CDCDomainsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.NameRegex.IsNull() || data.NameRegex.IsUnknown() {
+		return
+	}
+
+	if _, err := regexp.Compile(data.NameRegex.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name_regex"),
+			"Regular expression is not valid",
+			err.Error(),
+		)
+	}
+}
+
+func (d *CDCDomainsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *CDCDomainsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *CDCDomainsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domains, err := d.client.ListDomains(data.WhoisOwner.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error listing domains",
+			"READ: Could not list domains, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Cheap filters first, the others need one API call per domain.
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		nameRegex = regexp.MustCompile(data.NameRegex.ValueString())
+	}
+
+	names := []string{}
+	for _, domain := range domains {
+		name := strings.ToLower(domain.String())
+
+		if nameRegex != nil && !nameRegex.MatchString(name) {
+			continue
+		}
+
+		if len(data.TLDs) > 0 && !hasTLD(name, data.TLDs) {
+			continue
+		}
+
+		if !data.NameServerType.IsNull() {
+			details, err := d.client.GetDomain(domain)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error reading domain",
+					fmt.Sprintf("READ: Could not read domain %s, unexpected error: %s", domain, err.Error()),
+				)
+				return
+			}
+
+			if details.NameServerType != data.NameServerType.ValueString() {
+				continue
+			}
+		}
+
+		if !data.ExpiresWithinDays.IsNull() {
+			serviceInfos, err := d.client.GetServiceInfos(domain)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error reading domain",
+					fmt.Sprintf("READ: Could not read service infos of %s, unexpected error: %s", domain, err.Error()),
+				)
+				return
+			}
+
+			if !expiresWithin(serviceInfos.Expiration, data.ExpiresWithinDays.ValueInt64()) {
+				continue
+			}
+		}
+
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data.Names = []types.String{}
+	for _, name := range names {
+		data.Names = append(data.Names, types.StringValue(name))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// hasTLD reports whether name is under one of tlds, given with or without a
+// leading dot. Internationalised TLDs are compared in punycode, as names are.
+func hasTLD(name string, tlds []types.String) bool {
+	for _, tld := range tlds {
+		suffix := "." + dnsclient.CanonicalHost(strings.TrimPrefix(tld.ValueString(), "."))
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// expiresWithin reports whether the 2006-01-02 expiration date is less than
+// days away. Dates that cannot be parsed are kept, so they are not missed.
+func expiresWithin(expiration string, days int64) bool {
+	expiresAt, err := time.Parse("2006-01-02", expiration)
+	if err != nil {
+		return true
+	}
+
+	return time.Until(expiresAt) < time.Duration(days)*24*time.Hour
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHasTLD(t *testing.T) {
+	testCases := map[string]struct {
+		name string
+		tlds []string
+		want bool
+	}{
+		"same TLD":          {name: "example.com", tlds: []string{"com"}, want: true},
+		"leading dot":       {name: "example.com", tlds: []string{".com"}, want: true},
+		"uppercase":         {name: "example.com", tlds: []string{"COM"}, want: true},
+		"second level":      {name: "shop.co.uk", tlds: []string{"co.uk"}, want: true},
+		"one of many":       {name: "example.fr", tlds: []string{"com", "fr"}, want: true},
+		"unicode TLD":       {name: "example.xn--p1ai", tlds: []string{"рф"}, want: true},
+		"unicode with dot":  {name: "example.xn--p1ai", tlds: []string{".РФ"}, want: true},
+		"punycode TLD":      {name: "example.xn--p1ai", tlds: []string{"xn--p1ai"}, want: true},
+		"other TLD":         {name: "example.com", tlds: []string{"net"}, want: false},
+		"label suffix only": {name: "example.com", tlds: []string{"om"}, want: false},
+		"TLD not a suffix":  {name: "example.co.uk", tlds: []string{"co"}, want: false},
+		"other unicode TLD": {name: "example.com", tlds: []string{"рф"}, want: false},
+		"empty TLD":         {name: "example.com", tlds: []string{""}, want: false},
+		"no TLDs":           {name: "example.com", tlds: []string{}, want: false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tlds := []types.String{}
+			for _, tld := range testCase.tlds {
+				tlds = append(tlds, types.StringValue(tld))
+			}
+
+			if got := hasTLD(testCase.name, tlds); got != testCase.want {
+				t.Errorf("hasTLD(%q, %q) = %t, want %t", testCase.name, testCase.tlds, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestExpiresWithin(t *testing.T) {
+	day := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	testCases := map[string]struct {
+		expiration string
+		days       int64
+		want       bool
+	}{
+		"already expired":  {expiration: day(-10), days: 0, want: true},
+		"within":           {expiration: day(10), days: 30, want: true},
+		"beyond":           {expiration: day(60), days: 30, want: false},
+		"zero days":        {expiration: day(10), days: 0, want: false},
+		"unparsable":       {expiration: "soon", days: 30, want: true},
+		"timestamp format": {expiration: "2006-01-02T15:04:05Z", days: 0, want: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := expiresWithin(testCase.expiration, testCase.days); got != testCase.want {
+				t.Errorf("expiresWithin(%q, %d) = %t, want %t", testCase.expiration, testCase.days, got, testCase.want)
+			}
+		})
+	}
+}
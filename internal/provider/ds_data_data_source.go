@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/miekg/dns"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CDCDSDataDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &CDCDSDataDataSource{}
+
+func NewCDCDSDataDataSource() datasource.DataSource {
+	return &CDCDSDataDataSource{}
+}
+
+type CDCDSDataDataSource struct {
+	dns *dnsclient.Client
+}
+
+type CDCDSDataDataSourceModel struct {
+	ServiceName HostnameValue       `tfsdk:"service_name"`
+	DNSKey      types.String        `tfsdk:"dnskey"`
+	Server      types.String        `tfsdk:"server"`
+	Keys        []CDCDSDataKeyModel `tfsdk:"keys"`
+}
+
+// CDCDSDataKeyModel is a DNSKEY with the fields OVH expects for DS records
+// and the digests of its DS records. It is also the result of the
+// ds_data function.
+type CDCDSDataKeyModel struct {
+	KeyTag       types.Int64  `tfsdk:"key_tag"`
+	Algorithm    types.Int64  `tfsdk:"algorithm"`
+	Flags        types.Int64  `tfsdk:"flags"`
+	PublicKey    types.String `tfsdk:"public_key"`
+	DigestSHA256 types.String `tfsdk:"digest_sha256"`
+	DigestSHA384 types.String `tfsdk:"digest_sha384"`
+}
+
+func (d *CDCDSDataDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ds_data"
+}
+
+func (d *CDCDSDataDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Computes the DS data of a domain from its DNSKEY records, given in presentation format or fetched from a name server.",
+
+		Attributes: map[string]schema.Attribute{
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+			},
+			"dnskey": schema.StringAttribute{
+				Optional:    true,
+				Description: "DNSKEY records in presentation format, eg: the output of dig or only the rdata \"257 3 13 <public key>\". Conflicts with server.",
+			},
+			"server": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name server, host name or IP, to ask for the DNSKEY records of the domain. The provider dns_port is used. Conflicts with dnskey.",
+			},
+			"keys": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "DNSKEY records of the domain",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: dsDataKeyAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func dsDataKeyAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"key_tag": schema.Int64Attribute{
+			Computed:    true,
+			Description: "Key tag computed from the DNSKEY",
+		},
+		"algorithm": schema.Int64Attribute{
+			Computed:    true,
+			Description: "DNSSEC algorithm number",
+		},
+		"flags": schema.Int64Attribute{
+			Computed:    true,
+			Description: "DNSKEY flags, 257 for a key signing key or 256 for a zone signing key",
+		},
+		"public_key": schema.StringAttribute{
+			Computed:    true,
+			Description: "Base64 public key of the DNSKEY",
+		},
+		"digest_sha256": schema.StringAttribute{
+			Computed:    true,
+			Description: "Hex digest of the DS record using SHA-256 (digest type 2)",
+		},
+		"digest_sha384": schema.StringAttribute{
+			Computed:    true,
+			Description: "Hex digest of the DS record using SHA-384 (digest type 4)",
+		},
+	}
+}
+
+func (d *CDCDSDataDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("dnskey"),
+			path.MatchRoot("server"),
+		),
+	}
+}
+
+func (d *CDCDSDataDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.dns = providerData.DNS
+}
+
+func (d *CDCDSDataDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *CDCDSDataDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var keys []dnsclient.DNSKey
+	var err error
+	if !data.DNSKey.IsNull() {
+		keys, err = dnsclient.ParseDNSKeys(serviceName.String(), data.DNSKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("dnskey"),
+				"DNSKEY records are not valid",
+				err.Error(),
+			)
+			return
+		}
+	} else {
+		keys, err = d.fetchDNSKeys(ctx, data.Server.ValueString(), serviceName.String())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("server"),
+				"Could not fetch DNSKEY records",
+				fmt.Sprintf("READ: Could not read DNSKEY records of %s from %s: %s", serviceName, data.Server.ValueString(), err.Error()),
+			)
+			return
+		}
+	}
+
+	data.Keys, err = dsDataKeys(serviceName.String(), keys)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error computing DS data",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// fetchDNSKeys asks each address of server for the DNSKEY records of zone and
+// returns the first answer.
+func (d *CDCDSDataDataSource) fetchDNSKeys(ctx context.Context, server string, zone string) ([]dnsclient.DNSKey, error) {
+	addresses, err := d.dns.ServerAddresses(ctx, server, "")
+	if err != nil {
+		return nil, err
+	}
+
+	lastErr := fmt.Errorf("%s has no address", server)
+	for _, address := range addresses {
+		keys, err := d.dns.DNSKeys(ctx, address, zone)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%s has no DNSKEY record for %s", server, zone)
+		}
+
+		return keys, nil
+	}
+
+	return nil, lastErr
+}
+
+// dsDataKeys converts DNSKEY records of zone and computes their DS digests.
+func dsDataKeys(zone string, keys []dnsclient.DNSKey) ([]CDCDSDataKeyModel, error) {
+	dsDataKeys := make([]CDCDSDataKeyModel, 0, len(keys))
+
+	for _, key := range keys {
+		sha256, err := dnsclient.DSDigest(zone, key, dns.SHA256)
+		if err != nil {
+			return nil, err
+		}
+
+		sha384, err := dnsclient.DSDigest(zone, key, dns.SHA384)
+		if err != nil {
+			return nil, err
+		}
+
+		dsDataKeys = append(dsDataKeys, CDCDSDataKeyModel{
+			KeyTag:       types.Int64Value(int64(key.KeyTag)),
+			Algorithm:    types.Int64Value(int64(key.Algorithm)),
+			Flags:        types.Int64Value(int64(key.Flags)),
+			PublicKey:    types.StringValue(key.PublicKey),
+			DigestSHA256: types.StringValue(sha256),
+			DigestSHA384: types.StringValue(sha384),
+		})
+	}
+
+	return dsDataKeys, nil
+}
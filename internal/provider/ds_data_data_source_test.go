@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/miekg/dns"
+)
+
+// rfc6605Key is the ECDSA P-256 DNSKEY of example.net. from RFC 6605 section
+// 6.1, its SHA-256 DS digest is published there.
+const rfc6605Key = "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="
+
+func TestDSDataKeys(t *testing.T) {
+	keys := []dnsclient.DNSKey{
+		{Flags: 257, Protocol: 3, Algorithm: 13, PublicKey: rfc6605Key, KeyTag: 55648},
+	}
+
+	got, err := dsDataKeys("example.net", keys)
+	if err != nil {
+		t.Fatalf("dsDataKeys returned %q", err)
+	}
+
+	want := []CDCDSDataKeyModel{{
+		KeyTag:       types.Int64Value(55648),
+		Algorithm:    types.Int64Value(13),
+		Flags:        types.Int64Value(257),
+		PublicKey:    types.StringValue(rfc6605Key),
+		DigestSHA256: types.StringValue("B4C8C1FE2E7477127B27115656AD6256F424625BF5C1E2770CE6D6E37DF61D17"),
+		DigestSHA384: types.StringValue("3BE4B980B34443E569255F4A347D4C8E8E18DE755FB8072D7B355C44C56B50A61E8050AE636041B9664A04F05AEF2680"),
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dsDataKeys = %+v, want %+v", got, want)
+	}
+}
+
+func TestFetchDNSKeys(t *testing.T) {
+	address := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+
+		question := req.Question[0]
+		switch question.Name {
+		case "example.net.":
+			resp.Answer = append(resp.Answer, &dns.DNSKEY{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600}, Flags: 257, Protocol: 3, Algorithm: 13, PublicKey: rfc6605Key})
+		case "unsigned.example.":
+		default:
+			resp.Rcode = dns.RcodeRefused
+		}
+
+		w.WriteMsg(resp)
+	}))
+
+	host, port, _ := net.SplitHostPort(address)
+	portNumber, _ := strconv.Atoi(port)
+	client, err := dnsclient.NewClient(dnsclient.Config{Resolver: address, Port: portNumber, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewClient returned %q", err)
+	}
+
+	testCases := map[string]struct {
+		zone     string
+		wantTags []uint16
+		wantErr  bool
+	}{
+		"signed zone":   {zone: "example.net", wantTags: []uint16{55648}},
+		"unsigned zone": {zone: "unsigned.example", wantErr: true},
+		"refused":       {zone: "example.com", wantErr: true},
+	}
+
+	dataSource := &CDCDSDataDataSource{dns: client}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keys, err := dataSource.fetchDNSKeys(context.Background(), host, testCase.zone)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("fetchDNSKeys(%q) error = %v, want error %t", testCase.zone, err, testCase.wantErr)
+			}
+
+			var tags []uint16
+			for _, key := range keys {
+				tags = append(tags, key.KeyTag)
+			}
+
+			if !reflect.DeepEqual(tags, testCase.wantTags) {
+				t.Errorf("fetchDNSKeys(%q) key tags = %v, want %v", testCase.zone, tags, testCase.wantTags)
+			}
+		})
+	}
+}
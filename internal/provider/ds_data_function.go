@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DSDataFunction{}
+
+func NewDSDataFunction() function.Function {
+	return &DSDataFunction{}
+}
+
+// DSDataFunction is the offline counterpart of the cdcovhns_ds_data data
+// source, for DNSKEY records given in presentation format.
+type DSDataFunction struct{}
+
+func (f *DSDataFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ds_data"
+}
+
+func (f *DSDataFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Computes DS data from DNSKEY records",
+		Description: "Parses DNSKEY records of a domain in presentation format and returns their key tag, algorithm, flags, public key and DS digests.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "service_name",
+				Description: "Domain name",
+			},
+			function.StringParameter{
+				Name:        "dnskey",
+				Description: "DNSKEY records in presentation format, eg: the output of dig or only the rdata \"257 3 13 <public key>\"",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.ObjectType{
+				AttrTypes: map[string]attr.Type{
+					"key_tag":       types.Int64Type,
+					"algorithm":     types.Int64Type,
+					"flags":         types.Int64Type,
+					"public_key":    types.StringType,
+					"digest_sha256": types.StringType,
+					"digest_sha384": types.StringType,
+				},
+			},
+		},
+	}
+}
+
+func (f *DSDataFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var serviceName, records string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &serviceName, &records))
+	if resp.Error != nil {
+		return
+	}
+
+	domain, err := api.ParseDomainName(serviceName)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Domain name is not valid: "+err.Error())
+		return
+	}
+
+	keys, err := dnsclient.ParseDNSKeys(domain.String(), records)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "DNSKEY records are not valid: "+err.Error())
+		return
+	}
+
+	dsDataKeys, err := dsDataKeys(domain.String(), keys)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, dsDataKeys))
+}
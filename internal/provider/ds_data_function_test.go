@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDSDataFunctionRun(t *testing.T) {
+	testCases := map[string]struct {
+		serviceName  string
+		records      string
+		wantKeyTags  []int64
+		wantArgument *int64
+		wantErr      string
+	}{
+		"rdata only": {
+			serviceName: "example.net",
+			records:     "257 3 13 " + rfc6605Key,
+			wantKeyTags: []int64{55648},
+		},
+		"full records": {
+			serviceName: "Example.NET.",
+			records:     "example.net. 3600 IN DNSKEY 257 3 13 " + rfc6605Key + "\nexample.net. 3600 IN DNSKEY 256 3 13 " + rfc6605Key,
+			wantKeyTags: []int64{55648, 55647},
+		},
+		"invalid domain": {
+			serviceName:  "example",
+			records:      "257 3 13 " + rfc6605Key,
+			wantArgument: int64Pointer(0),
+			wantErr:      "Domain name is not valid",
+		},
+		"record of another zone": {
+			serviceName:  "example.com",
+			records:      "example.net. IN DNSKEY 257 3 13 " + rfc6605Key,
+			wantArgument: int64Pointer(1),
+			wantErr:      "DNSKEY records are not valid",
+		},
+	}
+
+	ctx := context.Background()
+
+	definitionResp := &function.DefinitionResponse{}
+	NewDSDataFunction().Definition(ctx, function.DefinitionRequest{}, definitionResp)
+	returnType := definitionResp.Definition.Return.GetType()
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(testCase.serviceName),
+					types.StringValue(testCase.records),
+				}),
+			}
+			resp := &function.RunResponse{Result: function.NewResultData(types.ListUnknown(returnType.(types.ListType).ElemType))}
+
+			NewDSDataFunction().Run(ctx, req, resp)
+
+			if testCase.wantErr != "" {
+				if resp.Error == nil || !strings.Contains(resp.Error.Text, testCase.wantErr) {
+					t.Fatalf("Run error = %v, want one containing %q", resp.Error, testCase.wantErr)
+				}
+				if resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != *testCase.wantArgument {
+					t.Errorf("Run error argument = %v, want %d", resp.Error.FunctionArgument, *testCase.wantArgument)
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("Run returned %q", resp.Error)
+			}
+
+			var keys []CDCDSDataKeyModel
+			if diags := resp.Result.Value().(types.List).ElementsAs(ctx, &keys, false); diags.HasError() {
+				t.Fatalf("could not read the result: %v", diags)
+			}
+
+			if len(keys) != len(testCase.wantKeyTags) {
+				t.Fatalf("Run returned %d keys, want %d", len(keys), len(testCase.wantKeyTags))
+			}
+			for i, key := range keys {
+				if key.KeyTag.ValueInt64() != testCase.wantKeyTags[i] {
+					t.Errorf("key %d tag = %d, want %d", i, key.KeyTag.ValueInt64(), testCase.wantKeyTags[i])
+				}
+				if key.DigestSHA256.ValueString() == "" || key.DigestSHA384.ValueString() == "" {
+					t.Errorf("key %d has no digests: %+v", i, key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,490 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CDCDSRecordsResource{}
+var _ resource.ResourceWithImportState = &CDCDSRecordsResource{}
+var _ resource.ResourceWithModifyPlan = &CDCDSRecordsResource{}
+var _ resource.ResourceWithValidateConfig = &CDCDSRecordsResource{}
+
+// DNSSEC algorithm numbers accepted by registries, see RFC 8624.
+var dsAlgorithms = []int64{5, 7, 8, 10, 13, 14, 15, 16}
+
+// DNSKEY flags of a zone signing key and a key signing key.
+var dsFlags = []int64{256, 257}
+
+func NewCDCDSRecordsResource() resource.Resource {
+	return &CDCDSRecordsResource{}
+}
+
+type CDCDSRecordsResource struct {
+	client *api.APIClient
+}
+
+type CDCDSRecordsResourceModel struct {
+	ID          types.String          `tfsdk:"id"`
+	ServiceName HostnameValue         `tfsdk:"service_name"`
+	Keys        []CDCDSRecordKeyModel `tfsdk:"keys"`
+}
+
+type CDCDSRecordKeyModel struct {
+	KeyTag    types.Int64  `tfsdk:"key_tag"`
+	Algorithm types.Int64  `tfsdk:"algorithm"`
+	Flags     types.Int64  `tfsdk:"flags"`
+	PublicKey types.String `tfsdk:"public_key"`
+}
+
+func (r *CDCDSRecordsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ds_records"
+}
+
+func (r *CDCDSRecordsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "OVH DS records resource. Manages the full set of DNSSEC keys published at the registry for a domain.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					hostnameRequiresReplace(),
+				},
+			},
+			"keys": schema.SetNestedAttribute{
+				Required:    true,
+				Description: "DNSKEY records of the zone to publish as DS records. An empty set removes every DS record.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key_tag": schema.Int64Attribute{
+							Required:    true,
+							Description: "Key tag computed from the DNSKEY",
+							Validators: []validator.Int64{
+								int64validator.Between(0, 65535),
+							},
+						},
+						"algorithm": schema.Int64Attribute{
+							Required:    true,
+							Description: "DNSSEC algorithm number, eg: 13 for ECDSAP256SHA256",
+							Validators: []validator.Int64{
+								int64validator.OneOf(dsAlgorithms...),
+							},
+						},
+						"flags": schema.Int64Attribute{
+							Required:    true,
+							Description: "DNSKEY flags, 257 for a key signing key or 256 for a zone signing key",
+							Validators: []validator.Int64{
+								int64validator.OneOf(dsFlags...),
+							},
+						},
+						"public_key": schema.StringAttribute{
+							Required:    true,
+							Description: "Base64 public key of the DNSKEY",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *CDCDSRecordsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *CDCDSRecordsResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ServiceName.IsNull() && !data.ServiceName.IsUnknown() {
+		_, diags := parseServiceName(data.ServiceName)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	for _, key := range data.Keys {
+		if key.KeyTag.IsUnknown() || key.Algorithm.IsUnknown() || key.Flags.IsUnknown() || key.PublicKey.IsUnknown() {
+			continue
+		}
+
+		keyTag, err := dnsclient.KeyTag(uint16(key.Flags.ValueInt64()), uint8(key.Algorithm.ValueInt64()), key.PublicKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("keys"),
+				"Public key is not valid",
+				fmt.Sprintf("Key %d: %s", key.KeyTag.ValueInt64(), err.Error()),
+			)
+			continue
+		}
+
+		if int64(keyTag) != key.KeyTag.ValueInt64() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("keys"),
+				"Wrong key tag",
+				fmt.Sprintf("Key tag %d does not match its public key, flags and algorithm, expected %d", key.KeyTag.ValueInt64(), keyTag),
+			)
+		}
+	}
+}
+
+func (r *CDCDSRecordsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CDCDSRecordsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *CDCDSRecordsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// Nothing to check on destroy, or before the values are known.
+	if plan == nil || plan.ServiceName.IsUnknown() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state != nil && dsKeysEqual(plan.Keys, state.Keys) {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading domain",
+			"Could not read domain state, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(domainStateDiagnostics(domain, true)...)
+
+	if !domain.DNSSECSupported {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"DNSSEC not supported",
+			fmt.Sprintf("The registry of %s does not accept DS records through OVH", serviceName),
+		)
+	}
+
+	if domain.NameServerType == api.NSHosted {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("service_name"),
+			"Domain uses OVH name servers",
+			fmt.Sprintf("%s uses OVH name servers, OVH publishes their DS records itself when DNSSEC is enabled on the zone", serviceName),
+		)
+	}
+
+	if state == nil {
+		dsRecords, err := r.client.GetDSRecords(serviceName)
+		if err == nil && len(activeDSRecords(dsRecords)) > 0 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("keys"),
+				"DS records will be replaced",
+				fmt.Sprintf("%s already has %d DS records, they will be replaced by the configured keys", serviceName, len(activeDSRecords(dsRecords))),
+			)
+		}
+	}
+
+	currentTasks := r.client.CheckCurrentTaskState(serviceName)
+	if currentTasks != nil {
+		resp.Diagnostics.AddError(
+			"Some task are already in operation",
+			"Cannot do anything because some task on the domain are already in TODO or DOING state: \n"+currentTasks.Error(),
+		)
+	}
+}
+
+func (r *CDCDSRecordsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CDCDSRecordsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	task, err := r.client.UpdateDSRecords(serviceName, dsRecordKeys(data.Keys))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating DS records",
+			"CREATE: Could not update DS records, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// The DS records were sent, keep them in state even if waiting fails.
+	data.ID = types.StringValue(serviceName.String())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if err := waitForTask(ctx, r.client, serviceName, task.ID); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating DS records",
+			fmt.Sprintf("CREATE: Task %d did not complete: %s", task.ID, err.Error()),
+		)
+	}
+}
+
+func (r *CDCDSRecordsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CDCDSRecordsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dsRecords, err := r.client.GetDSRecords(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Domain not found",
+			fmt.Sprintf("READ: Domain %s is not in the OVH account anymore. Removing its DS records from state", serviceName),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading DS records",
+			"READ: Could not read DS records, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(serviceName.String())
+	data.Keys = convertDSRecordsToKeys(activeDSRecords(dsRecords), data.Keys)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCDSRecordsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state *CDCDSRecordsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !dsKeysEqual(plan.Keys, state.Keys) {
+		task, err := r.client.UpdateDSRecords(serviceName, dsRecordKeys(plan.Keys))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating DS records",
+				"UPDATE: Could not update DS records, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		if err := waitForTask(ctx, r.client, serviceName, task.ID); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating DS records",
+				fmt.Sprintf("UPDATE: Task %d did not complete, run 'terraform refresh' to read the current DS records: %s", task.ID, err.Error()),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *CDCDSRecordsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *CDCDSRecordsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	currentTasks := r.client.CheckCurrentTaskState(serviceName)
+	if currentTasks != nil {
+		resp.Diagnostics.AddError(
+			"Some task already in operation",
+			"DELETE: Cannot do anything because some task on the domain are already in TODO or DOING state: \n"+currentTasks.Error(),
+		)
+		return
+	}
+
+	task, err := r.client.UpdateDSRecords(serviceName, []api.DSRecordKey{})
+	if api.IsNotFound(err) {
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting DS records",
+			"DELETE: Could not remove DS records, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if err := waitForTask(ctx, r.client, serviceName, task.ID); err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting DS records",
+			fmt.Sprintf("DELETE: Task %d did not complete: %s", task.ID, err.Error()),
+		)
+	}
+}
+
+func (r *CDCDSRecordsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	serviceName, err := api.ParseDomainName(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected a domain name: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), serviceName.String())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), req.ID)...)
+}
+
+// activeDSRecords drops the DS records OVH is removing.
+func activeDSRecords(dsRecords []api.DSRecord) []api.DSRecord {
+	active := []api.DSRecord{}
+	for _, dsRecord := range dsRecords {
+		if dsRecord.Status != api.DSRecordDeleting && dsRecord.Status != api.DSRecordDeleted {
+			active = append(active, dsRecord)
+		}
+	}
+	return active
+}
+
+func dsRecordKeys(keys []CDCDSRecordKeyModel) []api.DSRecordKey {
+	dsRecordKeys := []api.DSRecordKey{}
+	for _, key := range keys {
+		dsRecordKeys = append(dsRecordKeys, api.DSRecordKey{
+			Algorithm: int(key.Algorithm.ValueInt64()),
+			Flags:     int(key.Flags.ValueInt64()),
+			PublicKey: publicKeyCanonical(key.PublicKey.ValueString()),
+			Tag:       int(key.KeyTag.ValueInt64()),
+		})
+	}
+	return dsRecordKeys
+}
+
+// convertDSRecordsToKeys builds the keys attribute from the API. Public keys
+// equal to prior ones keep their prior notation.
+func convertDSRecordsToKeys(dsRecords []api.DSRecord, prior []CDCDSRecordKeyModel) []CDCDSRecordKeyModel {
+	keys := []CDCDSRecordKeyModel{}
+
+	for _, dsRecord := range dsRecords {
+		key := CDCDSRecordKeyModel{
+			KeyTag:    types.Int64Value(int64(dsRecord.KeyTag)),
+			Algorithm: types.Int64Value(int64(dsRecord.Algorithm)),
+			Flags:     types.Int64Value(int64(dsRecord.Flags)),
+			PublicKey: types.StringValue(dsRecord.PublicKey),
+		}
+
+		for _, priorKey := range prior {
+			if publicKeyCanonical(priorKey.PublicKey.ValueString()) == publicKeyCanonical(dsRecord.PublicKey) {
+				key.PublicKey = priorKey.PublicKey
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// dsKeysEqual compares two key sets, ignoring public key notation.
+func dsKeysEqual(a, b []CDCDSRecordKeyModel) bool {
+	return strings.Join(dsKeysCanonical(a), "\n") == strings.Join(dsKeysCanonical(b), "\n")
+}
+
+func dsKeysCanonical(keys []CDCDSRecordKeyModel) []string {
+	canonical := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.KeyTag.IsUnknown() || key.Algorithm.IsUnknown() || key.Flags.IsUnknown() || key.PublicKey.IsUnknown() {
+			canonical = append(canonical, "unknown")
+			continue
+		}
+
+		canonical = append(canonical, fmt.Sprintf(
+			"%d %d %d %s",
+			key.KeyTag.ValueInt64(), key.Algorithm.ValueInt64(), key.Flags.ValueInt64(), publicKeyCanonical(key.PublicKey.ValueString()),
+		))
+	}
+	sort.Strings(canonical)
+
+	return canonical
+}
+
+func publicKeyCanonical(publicKey string) string {
+	return strings.Join(strings.Fields(publicKey), "")
+}
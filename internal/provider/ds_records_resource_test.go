@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestActiveDSRecords(t *testing.T) {
+	testCases := map[string]struct {
+		statuses []string
+		wantIDs  []int64
+	}{
+		"all active":       {statuses: []string{"enabled", "creating", ""}, wantIDs: []int64{1, 2, 3}},
+		"deleting dropped": {statuses: []string{"enabled", api.DSRecordDeleting}, wantIDs: []int64{1}},
+		"deleted dropped":  {statuses: []string{api.DSRecordDeleted, "creating"}, wantIDs: []int64{2}},
+		"all removed":      {statuses: []string{api.DSRecordDeleting, api.DSRecordDeleted}, wantIDs: []int64{}},
+		"none":             {statuses: []string{}, wantIDs: []int64{}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dsRecords := []api.DSRecord{}
+			for i, status := range testCase.statuses {
+				dsRecords = append(dsRecords, api.DSRecord{ID: int64(i + 1), Status: status})
+			}
+
+			ids := []int64{}
+			for _, dsRecord := range activeDSRecords(dsRecords) {
+				ids = append(ids, dsRecord.ID)
+			}
+
+			if !reflect.DeepEqual(ids, testCase.wantIDs) {
+				t.Errorf("activeDSRecords kept %v, want %v", ids, testCase.wantIDs)
+			}
+		})
+	}
+}
+
+func TestDSRecordKeys(t *testing.T) {
+	keys := []CDCDSRecordKeyModel{
+		dsKey(55648, 13, 257, "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSq\n\tQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="),
+	}
+
+	want := []api.DSRecordKey{
+		{Algorithm: 13, Flags: 257, PublicKey: rfc6605Key, Tag: 55648},
+	}
+
+	if got := dsRecordKeys(keys); !reflect.DeepEqual(got, want) {
+		t.Errorf("dsRecordKeys = %+v, want %+v", got, want)
+	}
+}
+
+func TestConvertDSRecordsToKeys(t *testing.T) {
+	wrapped := "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSq QpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="
+
+	testCases := map[string]struct {
+		prior []CDCDSRecordKeyModel
+		want  []CDCDSRecordKeyModel
+	}{
+		"no prior": {
+			want: []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key), dsKey(55647, 13, 256, rfc6605Key)},
+		},
+		"prior notation kept": {
+			prior: []CDCDSRecordKeyModel{dsKey(55648, 13, 257, wrapped)},
+			want:  []CDCDSRecordKeyModel{dsKey(55648, 13, 257, wrapped), dsKey(55647, 13, 256, wrapped)},
+		},
+		"other prior key": {
+			prior: []CDCDSRecordKeyModel{dsKey(10771, 14, 257, "xKYaNhWdGOfJ")},
+			want:  []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key), dsKey(55647, 13, 256, rfc6605Key)},
+		},
+	}
+
+	dsRecords := []api.DSRecord{
+		{ID: 1, KeyTag: 55648, Algorithm: 13, Flags: 257, PublicKey: rfc6605Key},
+		{ID: 2, KeyTag: 55647, Algorithm: 13, Flags: 256, PublicKey: rfc6605Key},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := convertDSRecordsToKeys(dsRecords, testCase.prior); !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("convertDSRecordsToKeys = %+v, want %+v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestDSKeysEqual(t *testing.T) {
+	testCases := map[string]struct {
+		a    []CDCDSRecordKeyModel
+		b    []CDCDSRecordKeyModel
+		want bool
+	}{
+		"same keys": {
+			a:    []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key)},
+			b:    []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key)},
+			want: true,
+		},
+		"other order": {
+			a:    []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key), dsKey(55647, 13, 256, rfc6605Key)},
+			b:    []CDCDSRecordKeyModel{dsKey(55647, 13, 256, rfc6605Key), dsKey(55648, 13, 257, rfc6605Key)},
+			want: true,
+		},
+		"wrapped public key": {
+			a:    []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key)},
+			b:    []CDCDSRecordKeyModel{dsKey(55648, 13, 257, "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSq\nQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==")},
+			want: true,
+		},
+		"other flags": {
+			a: []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key)},
+			b: []CDCDSRecordKeyModel{dsKey(55648, 13, 256, rfc6605Key)},
+		},
+		"key added": {
+			a: []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key)},
+			b: []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key), dsKey(55647, 13, 256, rfc6605Key)},
+		},
+		"unknown key": {
+			a: []CDCDSRecordKeyModel{dsKey(55648, 13, 257, rfc6605Key)},
+			b: []CDCDSRecordKeyModel{{KeyTag: types.Int64Unknown(), Algorithm: types.Int64Value(13), Flags: types.Int64Value(257), PublicKey: types.StringValue(rfc6605Key)}},
+		},
+		"no keys": {
+			a:    []CDCDSRecordKeyModel{},
+			b:    nil,
+			want: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := dsKeysEqual(testCase.a, testCase.b); got != testCase.want {
+				t.Errorf("dsKeysEqual = %t, want %t", got, testCase.want)
+			}
+		})
+	}
+}
+
+func dsKey(keyTag int64, algorithm int64, flags int64, publicKey string) CDCDSRecordKeyModel {
+	return CDCDSRecordKeyModel{
+		KeyTag:    types.Int64Value(keyTag),
+		Algorithm: types.Int64Value(algorithm),
+		Flags:     types.Int64Value(flags),
+		PublicKey: types.StringValue(publicKey),
+	}
+}
@@ -0,0 +1,428 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CDCGlueRecordResource{}
+var _ resource.ResourceWithImportState = &CDCGlueRecordResource{}
+var _ resource.ResourceWithModifyPlan = &CDCGlueRecordResource{}
+var _ resource.ResourceWithValidateConfig = &CDCGlueRecordResource{}
+
+func NewCDCGlueRecordResource() resource.Resource {
+	return &CDCGlueRecordResource{}
+}
+
+type CDCGlueRecordResource struct {
+	client *api.APIClient
+}
+
+type CDCGlueRecordResourceModel struct {
+	ID          types.String  `tfsdk:"id"`
+	ServiceName HostnameValue `tfsdk:"service_name"`
+	Host        HostnameValue `tfsdk:"host"`
+	IPs         types.Set     `tfsdk:"ips"`
+}
+
+func (r *CDCGlueRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_glue_record"
+}
+
+func (r *CDCGlueRecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "OVH glue record resource",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain and host, separated by a slash",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_name": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Domain name",
+				PlanModifiers: []planmodifier.String{
+					hostnameRequiresReplace(),
+				},
+			},
+			"host": schema.StringAttribute{
+				Required:    true,
+				CustomType:  HostnameType{},
+				Description: "Host name inside the domain, eg: ns1.example.com",
+				PlanModifiers: []planmodifier.String{
+					hostnameRequiresReplace(),
+				},
+			},
+			"ips": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "IPv4 and IPv6 addresses of the host",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *CDCGlueRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *CDCGlueRecordResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ServiceName.IsNull() && !data.ServiceName.IsUnknown() {
+		_, diags := parseServiceName(data.ServiceName)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	var ips []types.String
+	if !data.IPs.IsUnknown() && !data.IPs.IsNull() {
+		resp.Diagnostics.Append(data.IPs.ElementsAs(ctx, &ips, false)...)
+	}
+
+	resp.Diagnostics.Append(validateGlueRecord(data.ServiceName, data.Host, ips)...)
+}
+
+func (r *CDCGlueRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CDCGlueRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *CDCGlueRecordResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// Nothing to check on destroy, or before the values are known.
+	if plan == nil || plan.ServiceName.IsUnknown() || plan.Host.IsUnknown() || plan.IPs.IsUnknown() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state != nil && state.IPs.Equal(plan.IPs) {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_name"),
+			"Domain not found",
+			fmt.Sprintf("Domain %s is not in the OVH account of the configured credentials", serviceName),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading domain",
+			"Could not read domain state, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(domainStateDiagnostics(domain, true)...)
+
+	var ips []string
+	resp.Diagnostics.Append(plan.IPs.ElementsAs(ctx, &ips, false)...)
+
+	if len(ips) > 1 && !domain.GlueRecordMultiIPSupported {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ips"),
+			"Multiple glue IPs not supported",
+			fmt.Sprintf("OVH accepts a single ip per glue record for %s", serviceName),
+		)
+	}
+
+	for _, ip := range ips {
+		if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil && !domain.GlueRecordIPv6Supported {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ips"),
+				"IPv6 glue records not supported",
+				fmt.Sprintf("OVH does not accept IPv6 glue records for %s, got %s", serviceName, ip),
+			)
+		}
+	}
+
+	if state == nil {
+		_, err := r.client.GetGlueRecord(serviceName, plan.Host.Canonical())
+		if err == nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("host"),
+				"Glue record already exists",
+				fmt.Sprintf(
+					"A glue record for %s already exists, import it first:\n"+
+						"terraform import cdcovhns_glue_record.<YOUR_RESOURCE_NAME> %s/%s",
+					plan.Host.ValueString(), serviceName, plan.Host.Canonical(),
+				),
+			)
+		}
+	}
+
+	currentTasks := r.client.CheckCurrentTaskState(serviceName)
+	if currentTasks != nil {
+		resp.Diagnostics.AddError(
+			"Some task are already in operation",
+			"Cannot do anything because some task on the domain are already in TODO or DOING state: \n"+currentTasks.Error(),
+		)
+	}
+}
+
+func (r *CDCGlueRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CDCGlueRecordResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ips []string
+	resp.Diagnostics.Append(data.IPs.ElementsAs(ctx, &ips, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	host := data.Host.Canonical()
+	task, err := r.client.CreateGlueRecord(serviceName, &api.GlueRecord{
+		Host: host,
+		IPs:  ips,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating glue record",
+			"CREATE: Could not create glue record, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Keep the record in state even if waiting fails, it exists at OVH.
+	data.ID = types.StringValue(glueRecordID(serviceName, host))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if err := waitForTask(ctx, r.client, serviceName, task.ID); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating glue record",
+			fmt.Sprintf("CREATE: Task %d did not complete: %s", task.ID, err.Error()),
+		)
+	}
+}
+
+func (r *CDCGlueRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CDCGlueRecordResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	glueRecord, err := r.client.GetGlueRecord(serviceName, data.Host.Canonical())
+	if api.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading glue record",
+			"READ: Could not read glue record, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	ips, diags := glueRecordIPs(ctx, glueRecord.IPs, data.IPs)
+	resp.Diagnostics.Append(diags...)
+
+	data.ID = types.StringValue(glueRecordID(serviceName, data.Host.Canonical()))
+	data.IPs = ips
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CDCGlueRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state *CDCGlueRecordResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.IPs.Equal(state.IPs) {
+		var ips []string
+		resp.Diagnostics.Append(plan.IPs.ElementsAs(ctx, &ips, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		task, err := r.client.UpdateGlueRecord(serviceName, plan.Host.Canonical(), ips)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating glue record",
+				"UPDATE: Could not update glue record, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		if err := waitForTask(ctx, r.client, serviceName, task.ID); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating glue record",
+				fmt.Sprintf("UPDATE: Task %d did not complete, run 'terraform refresh' to read the current ips: %s", task.ID, err.Error()),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *CDCGlueRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *CDCGlueRecordResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	currentTasks := r.client.CheckCurrentTaskState(serviceName)
+	if currentTasks != nil {
+		resp.Diagnostics.AddError(
+			"Some task already in operation",
+			"DELETE: Cannot do anything because some task on the domain are already in TODO or DOING state: \n"+currentTasks.Error(),
+		)
+		return
+	}
+
+	task, err := r.client.DeleteGlueRecord(serviceName, data.Host.Canonical())
+	if api.IsNotFound(err) {
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting glue record",
+			"DELETE: Could not delete glue record, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if err := waitForTask(ctx, r.client, serviceName, task.ID); err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting glue record",
+			fmt.Sprintf("DELETE: Task %d did not complete: %s", task.ID, err.Error()),
+		)
+	}
+}
+
+func (r *CDCGlueRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	domain, host, found := strings.Cut(req.ID, "/")
+	if !found || host == "" {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected <DOMAIN_NAME>/<HOST>, eg: example.com/ns1.example.com",
+		)
+		return
+	}
+
+	serviceName, err := api.ParseDomainName(domain)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected a domain name: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), glueRecordID(serviceName, NewHostnameValue(host).Canonical()))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("host"), host)...)
+}
+
+func glueRecordID(serviceName api.DomainName, host string) string {
+	return fmt.Sprintf("%s/%s", serviceName, host)
+}
+
+// glueRecordIPs builds the ips attribute from the API. IPs equal to prior ones
+// keep their prior notation.
+func glueRecordIPs(ctx context.Context, ips []string, prior types.Set) (types.Set, diag.Diagnostics) {
+	var priorIPs []string
+	if !prior.IsNull() && !prior.IsUnknown() {
+		prior.ElementsAs(ctx, &priorIPs, false)
+	}
+
+	values := []attr.Value{}
+	for _, ip := range ips {
+		for _, priorIP := range priorIPs {
+			if sameIP(ip, priorIP) {
+				ip = priorIP
+			}
+		}
+		values = append(values, types.StringValue(ip))
+	}
+
+	return types.SetValue(types.StringType, values)
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGlueRecordIPs(t *testing.T) {
+	testCases := map[string]struct {
+		ips   []string
+		prior types.Set
+		want  []string
+	}{
+		"no prior": {
+			ips:   []string{"192.0.2.1", "2001:db8::1"},
+			prior: types.SetNull(types.StringType),
+			want:  []string{"192.0.2.1", "2001:db8::1"},
+		},
+		"unknown prior": {
+			ips:   []string{"2001:db8::1"},
+			prior: types.SetUnknown(types.StringType),
+			want:  []string{"2001:db8::1"},
+		},
+		"prior notation kept": {
+			ips:   []string{"2001:db8::1", "192.0.2.1"},
+			prior: stringSet("2001:DB8:0:0::1", "192.0.2.1"),
+			want:  []string{"192.0.2.1", "2001:DB8:0:0::1"},
+		},
+		"changed ip shown as returned": {
+			ips:   []string{"2001:db8::2"},
+			prior: stringSet("2001:DB8::1"),
+			want:  []string{"2001:db8::2"},
+		},
+		"ip added outside terraform": {
+			ips:   []string{"192.0.2.1", "192.0.2.2"},
+			prior: stringSet("192.0.2.1"),
+			want:  []string{"192.0.2.1", "192.0.2.2"},
+		},
+		"ip removed outside terraform": {
+			ips:   []string{"192.0.2.2"},
+			prior: stringSet("192.0.2.1", "192.0.2.2"),
+			want:  []string{"192.0.2.2"},
+		},
+	}
+
+	ctx := context.Background()
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			set, diags := glueRecordIPs(ctx, testCase.ips, testCase.prior)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			var got []string
+			set.ElementsAs(ctx, &got, false)
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("glueRecordIPs(%q, %s) = %q, want %q", testCase.ips, testCase.prior, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestValidateGlueRecord(t *testing.T) {
+	testCases := map[string]struct {
+		serviceName   HostnameValue
+		host          HostnameValue
+		ips           []types.String
+		wantSummaries []string
+	}{
+		"valid": {
+			serviceName: NewHostnameValue("example.com"),
+			host:        NewHostnameValue("ns1.example.com"),
+			ips:         []types.String{types.StringValue("198.51.100.1"), types.StringValue("2001:4860::1")},
+		},
+		"zone apex": {
+			serviceName: NewHostnameValue("example.com"),
+			host:        NewHostnameValue("Example.COM."),
+			ips:         []types.String{types.StringValue("198.51.100.1")},
+		},
+		"host outside the domain": {
+			serviceName:   NewHostnameValue("example.com"),
+			host:          NewHostnameValue("ns1.example.net"),
+			ips:           []types.String{types.StringValue("198.51.100.1")},
+			wantSummaries: []string{"Host outside the domain"},
+		},
+		"suffix is not a parent": {
+			serviceName:   NewHostnameValue("example.com"),
+			host:          NewHostnameValue("ns1.myexample.com"),
+			ips:           []types.String{types.StringValue("198.51.100.1")},
+			wantSummaries: []string{"Host outside the domain"},
+		},
+		"invalid host": {
+			serviceName:   NewHostnameValue("example.com"),
+			host:          NewHostnameValue("ns_1.example.com"),
+			ips:           []types.String{types.StringValue("198.51.100.1")},
+			wantSummaries: []string{"Host is not valid"},
+		},
+		"invalid ip": {
+			serviceName:   NewHostnameValue("example.com"),
+			host:          NewHostnameValue("ns1.example.com"),
+			ips:           []types.String{types.StringValue("198.51.100"), types.StringValue("::ffff:198.51.100.1")},
+			wantSummaries: []string{"IP is not valid", "IP is not valid"},
+		},
+		"private ip": {
+			serviceName:   NewHostnameValue("example.com"),
+			host:          NewHostnameValue("ns1.example.com"),
+			ips:           []types.String{types.StringValue("10.0.0.1")},
+			wantSummaries: []string{"IP is not public"},
+		},
+		"duplicate in other notation": {
+			serviceName:   NewHostnameValue("example.com"),
+			host:          NewHostnameValue("ns1.example.com"),
+			ips:           []types.String{types.StringValue("2001:4860::1"), types.StringValue("2001:4860:0::1")},
+			wantSummaries: []string{"Duplicate glue IP"},
+		},
+		"unknown values skipped": {
+			serviceName: NewHostnameUnknown(),
+			host:        NewHostnameValue("ns1.example.net"),
+			ips:         []types.String{types.StringUnknown(), types.StringValue("198.51.100.1")},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := validateGlueRecord(testCase.serviceName, testCase.host, testCase.ips)
+
+			var summaries []string
+			for _, diagnostic := range diags {
+				summaries = append(summaries, diagnostic.Summary())
+			}
+
+			if strings.Join(summaries, ", ") != strings.Join(testCase.wantSummaries, ", ") {
+				t.Errorf("got diagnostics [%s], want [%s]", strings.Join(summaries, ", "), strings.Join(testCase.wantSummaries, ", "))
+			}
+		})
+	}
+}
+
+func stringSet(values ...string) types.Set {
+	elements := []attr.Value{}
+	for _, value := range values {
+		elements = append(elements, types.StringValue(value))
+	}
+
+	return types.SetValueMust(types.StringType, elements)
+}
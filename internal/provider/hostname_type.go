@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ basetypes.StringTypable = HostnameType{}
+var _ basetypes.StringValuableWithSemanticEquals = HostnameValue{}
+
+// HostnameType is a string type for DNS names. Values that differ only by
+// case, a trailing dot or Unicode/punycode notation are semantically equal.
+type HostnameType struct {
+	basetypes.StringType
+}
+
+func (t HostnameType) String() string {
+	return "HostnameType"
+}
+
+func (t HostnameType) ValueType(ctx context.Context) attr.Value {
+	return HostnameValue{}
+}
+
+func (t HostnameType) Equal(o attr.Type) bool {
+	other, ok := o.(HostnameType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t HostnameType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return HostnameValue{
+		StringValue: in,
+	}, nil
+}
+
+func (t HostnameType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+
+	return stringValuable, nil
+}
+
+type HostnameValue struct {
+	basetypes.StringValue
+}
+
+func NewHostnameValue(value string) HostnameValue {
+	return HostnameValue{
+		StringValue: basetypes.NewStringValue(value),
+	}
+}
+
+func NewHostnameNull() HostnameValue {
+	return HostnameValue{
+		StringValue: basetypes.NewStringNull(),
+	}
+}
+
+func NewHostnameUnknown() HostnameValue {
+	return HostnameValue{
+		StringValue: basetypes.NewStringUnknown(),
+	}
+}
+
+func (v HostnameValue) Type(ctx context.Context) attr.Type {
+	return HostnameType{}
+}
+
+func (v HostnameValue) Equal(o attr.Value) bool {
+	other, ok := o.(HostnameValue)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v HostnameValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(HostnameValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+
+		return false, diags
+	}
+
+	return v.SemanticallyEqual(newValue), diags
+}
+
+// SemanticallyEqual compares the canonical forms of two known host names.
+func (v HostnameValue) SemanticallyEqual(other HostnameValue) bool {
+	return dnsclient.CanonicalHost(v.ValueString()) == dnsclient.CanonicalHost(other.ValueString())
+}
+
+// Canonical returns the lowercase ASCII form without a trailing dot.
+func (v HostnameValue) Canonical() string {
+	return dnsclient.CanonicalHost(v.ValueString())
+}
+
+// hostnameRequiresReplace replaces the resource when a host name changes, but
+// not when only its notation does.
+func hostnameRequiresReplace() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = !NewHostnameValue(req.StateValue.ValueString()).SemanticallyEqual(NewHostnameValue(req.PlanValue.ValueString()))
+		},
+		"Changing the host name, other than its notation, replaces the resource.",
+		"Changing the host name, other than its notation, replaces the resource.",
+	)
+}
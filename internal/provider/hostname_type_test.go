@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHostnameValueSemanticallyEqual(t *testing.T) {
+	testCases := map[string]struct {
+		a         string
+		b         string
+		want      bool
+		canonical string
+	}{
+		"same":                   {a: "ns1.example.com", b: "ns1.example.com", want: true, canonical: "ns1.example.com"},
+		"case":                   {a: "NS1.Example.COM", b: "ns1.example.com", want: true, canonical: "ns1.example.com"},
+		"trailing dot":           {a: "ns1.example.com.", b: "ns1.example.com", want: true, canonical: "ns1.example.com"},
+		"case and trailing dot":  {a: "Example.COM.", b: "example.com", want: true, canonical: "example.com"},
+		"unicode and punycode":   {a: "bücher.example", b: "xn--bcher-kva.example", want: true, canonical: "xn--bcher-kva.example"},
+		"unicode uppercase":      {a: "BÜCHER.example.", b: "xn--bcher-kva.example", want: true, canonical: "xn--bcher-kva.example"},
+		"other host":             {a: "ns1.example.com", b: "ns2.example.com", want: false, canonical: "ns1.example.com"},
+		"other TLD":              {a: "example.com", b: "example.net", want: false, canonical: "example.com"},
+		"other unicode":          {a: "bücher.example", b: "bucher.example", want: false, canonical: "xn--bcher-kva.example"},
+		"subdomain":              {a: "ns1.example.com", b: "example.com", want: false, canonical: "ns1.example.com"},
+		"two trailing dots kept": {a: "example.com..", b: "example.com", want: false},
+	}
+
+	ctx := context.Background()
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a, b := NewHostnameValue(testCase.a), NewHostnameValue(testCase.b)
+
+			if got := a.SemanticallyEqual(b); got != testCase.want {
+				t.Errorf("SemanticallyEqual(%q, %q) = %t, want %t", testCase.a, testCase.b, got, testCase.want)
+			}
+
+			if got := b.SemanticallyEqual(a); got != testCase.want {
+				t.Errorf("SemanticallyEqual(%q, %q) = %t, want %t", testCase.b, testCase.a, got, testCase.want)
+			}
+
+			got, diags := a.StringSemanticEquals(ctx, b)
+			if diags.HasError() || got != testCase.want {
+				t.Errorf("StringSemanticEquals(%q, %q) = %t, %v, want %t", testCase.a, testCase.b, got, diags, testCase.want)
+			}
+
+			if testCase.canonical != "" && a.Canonical() != testCase.canonical {
+				t.Errorf("Canonical(%q) = %q, want %q", testCase.a, a.Canonical(), testCase.canonical)
+			}
+
+			// Plain equality still tells notations apart, so state keeps the
+			// configured one.
+			if testCase.a != testCase.b && a.Equal(b) {
+				t.Errorf("Equal(%q, %q) = true, want false", testCase.a, testCase.b)
+			}
+		})
+	}
+}
+
+func TestHostnameValueStringSemanticEqualsWrongType(t *testing.T) {
+	_, diags := NewHostnameValue("example.com").StringSemanticEquals(context.Background(), types.StringValue("example.com"))
+	if !diags.HasError() {
+		t.Error("comparing with a plain string returned no error")
+	}
+}
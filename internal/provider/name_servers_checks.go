@@ -0,0 +1,493 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	defaultPropagationTimeout  time.Duration = time.Hour
+	defaultPropagationInterval time.Duration = 30 * time.Second
+)
+
+// nameServersChanged reports whether the host/ip pairs differ between two
+// name server sets, ignoring computed attributes and host notation.
+func nameServersChanged(planned, current []CDCNameServersModel) bool {
+	if len(planned) != len(current) {
+		return true
+	}
+
+	currentPairs := make(map[string]string, len(current))
+	for _, nameServer := range current {
+		currentPairs[nameServer.Host.Canonical()] = nameServer.IP.ValueString()
+	}
+
+	for _, nameServer := range planned {
+		if nameServer.Host.IsUnknown() || nameServer.IP.IsUnknown() {
+			return true
+		}
+
+		ip, ok := currentPairs[nameServer.Host.Canonical()]
+		if !ok || !sameIP(ip, nameServer.IP.ValueString()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// domainStateDiagnostics describes the domain states in which name server
+// changes cannot be applied by OVH. They are errors when block is set, eg: a
+// change is planned, and warnings otherwise.
+func domainStateDiagnostics(domain api.Domain, block bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	add := func(summary string, detail string) {
+		if block {
+			diags.AddAttributeError(path.Root("service_name"), summary, detail)
+		} else {
+			diags.AddAttributeWarning(path.Root("service_name"), summary, detail)
+		}
+	}
+
+	expired := domain.State == api.DomainStateExpired
+	if expiration, err := time.Parse(time.RFC3339, domain.Expiration); err == nil && expiration.Before(time.Now()) {
+		expired = true
+	}
+
+	switch {
+	case expired:
+		add(
+			"Domain expired",
+			fmt.Sprintf("Domain %s expired, renew it in OVH Panel before changing its name servers", domain.Domain),
+		)
+	case domain.State == api.DomainStatePendingDelete || domain.State == api.DomainStateRestorable:
+		add(
+			"Domain being deleted",
+			fmt.Sprintf("Domain %s is in %s state and will be deleted by the registry unless it is restored in OVH Panel", domain.Domain, domain.State),
+		)
+	case domain.State == api.DomainStateDispute:
+		add(
+			"Domain in dispute",
+			fmt.Sprintf("Domain %s is in dispute, the registry may refuse any change", domain.Domain),
+		)
+	case domain.State != "" && domain.State != api.DomainStateOK:
+		diags.AddAttributeWarning(
+			path.Root("service_name"),
+			"Unexpected domain state",
+			fmt.Sprintf("Domain %s is in %s state, OVH may refuse to change its name servers", domain.Domain, domain.State),
+		)
+	}
+
+	if domain.SuspensionState == api.DomainSuspended {
+		add(
+			"Domain suspended",
+			fmt.Sprintf("Domain %s is suspended, it does not resolve and its name servers cannot be changed", domain.Domain),
+		)
+	}
+
+	if domain.TransferLockStatus == api.TransferLockLocking || domain.TransferLockStatus == api.TransferLockUnlocking {
+		diags.AddAttributeWarning(
+			path.Root("service_name"),
+			"Transfer lock changing",
+			fmt.Sprintf("Transfer lock of %s is %s, OVH may refuse other changes until it is done", domain.Domain, domain.TransferLockStatus),
+		)
+	}
+
+	return diags
+}
+
+// glueSupportDiagnostics reports glue ips that OVH does not accept for the
+// domain, according to the registry support flags of the domain.
+func glueSupportDiagnostics(domain api.Domain, nameServers []CDCNameServersModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, nameServer := range nameServers {
+		if nameServer.IP.IsUnknown() || nameServer.IP.ValueString() == "" {
+			continue
+		}
+
+		host := nameServer.Host.ValueString()
+		ip := net.ParseIP(nameServer.IP.ValueString())
+		if ip == nil {
+			continue
+		}
+
+		if !domain.HostSupported {
+			diags.AddAttributeError(
+				nameServerPath(nameServer).AtName("ip"),
+				"Glue records not supported",
+				fmt.Sprintf("OVH does not accept glue records for %s, %s needs a name server outside the domain", domain.Domain, host),
+			)
+			continue
+		}
+
+		if ip.To4() == nil && !domain.GlueRecordIPv6Supported {
+			diags.AddAttributeError(
+				nameServerPath(nameServer).AtName("ip"),
+				"IPv6 glue records not supported",
+				fmt.Sprintf("OVH does not accept IPv6 glue records for %s, got %s for %s", domain.Domain, ip.String(), host),
+			)
+		}
+	}
+
+	return diags
+}
+
+// preflightCheckNameServers queries every planned name server and reports the
+// ones that do not serve the zone of serviceName authoritatively. Entries whose
+// values are not known yet are skipped.
+func preflightCheckNameServers(ctx context.Context, dns *dnsclient.Client, serviceName string, nameServers []CDCNameServersModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, nameServer := range nameServers {
+		if nameServer.Host.IsUnknown() || nameServer.IP.IsUnknown() {
+			continue
+		}
+
+		host := nameServer.Host.ValueString()
+		servers, err := dns.ServerAddresses(ctx, host, nameServer.IP.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				nameServerPath(nameServer).AtName("host"),
+				"Name server pre-flight check failed",
+				fmt.Sprintf("Could not find an address for %s: %s", host, err.Error()),
+			)
+			continue
+		}
+
+		for _, server := range servers {
+			if err := dns.CheckAuthoritative(ctx, server, serviceName); err != nil {
+				diags.AddAttributeError(
+					nameServerPath(nameServer).AtName("host"),
+					"Name server pre-flight check failed",
+					fmt.Sprintf("%s is not ready to serve %s: %s", host, serviceName, err.Error()),
+				)
+			}
+		}
+	}
+
+	return diags
+}
+
+// dnssecCheckNameServers reads the DS records of serviceName from OVH and
+// reports the planned name servers that serve no DNSKEY matching one of them.
+// Mismatches are only warnings when allowMismatch is set. DS records that
+// cannot be read and name servers that cannot be queried are warnings, as
+// they prove no mismatch.
+func dnssecCheckNameServers(ctx context.Context, client *api.APIClient, dns *dnsclient.Client, serviceName api.DomainName, nameServers []CDCNameServersModel, allowMismatch bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	dsRecords, err := client.GetDSRecords(serviceName)
+	if err != nil {
+		diags.AddAttributeWarning(
+			path.Root("name_servers"),
+			"DNSSEC check skipped",
+			fmt.Sprintf("Could not read the DS records of %s, the planned name servers were not checked against them: %s", serviceName, err.Error()),
+		)
+		return diags
+	}
+
+	// Unsigned domains cannot break.
+	dsRecords = activeDSRecords(dsRecords)
+	if len(dsRecords) == 0 {
+		return diags
+	}
+
+	keyTags := make([]string, 0, len(dsRecords))
+	for _, dsRecord := range dsRecords {
+		keyTags = append(keyTags, strconv.Itoa(dsRecord.KeyTag))
+	}
+
+	var mismatches, failures []string
+	for _, server := range lookupNameServers(ctx, dns, nameServers) {
+		if server.err != nil {
+			failures = append(failures, server.err.Error())
+			continue
+		}
+
+		for _, address := range server.addresses {
+			keys, err := dns.DNSKeys(ctx, address, serviceName.String())
+			if err != nil {
+				failures = append(failures, err.Error())
+				continue
+			}
+
+			if !dsRecordsMatch(dsRecords, keys) {
+				mismatches = append(mismatches, fmt.Sprintf("%s (%s) serves no DNSKEY matching key tags %s", server.host, address, strings.Join(keyTags, ", ")))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		diags.AddAttributeWarning(
+			path.Root("name_servers"),
+			"DNSSEC check incomplete",
+			fmt.Sprintf("DS records of %s are published at the registry, but these name servers could not be checked:\n%s", serviceName, strings.Join(failures, "\n")),
+		)
+	}
+
+	if len(mismatches) == 0 {
+		return diags
+	}
+
+	summary := "DNSSEC check failed"
+	detail := fmt.Sprintf(
+		"DS records of %s are published at the registry, validating resolvers would fail to resolve the domain:\n%s\n"+
+			"Publish the keys on the planned name servers or update the DS records first, or set allow_dnssec_mismatch.",
+		serviceName, strings.Join(mismatches, "\n"),
+	)
+
+	if allowMismatch {
+		diags.AddAttributeWarning(path.Root("name_servers"), summary, detail)
+	} else {
+		diags.AddAttributeError(path.Root("name_servers"), summary, detail)
+	}
+
+	return diags
+}
+
+// dsRecordsMatch reports whether one of the keys is published as a DS record.
+func dsRecordsMatch(dsRecords []api.DSRecord, keys []dnsclient.DNSKey) bool {
+	for _, dsRecord := range dsRecords {
+		for _, key := range keys {
+			if dsRecord.Algorithm != int(key.Algorithm) || dsRecord.KeyTag != int(key.KeyTag) {
+				continue
+			}
+
+			if dsRecord.PublicKey == "" || strings.Join(strings.Fields(dsRecord.PublicKey), "") == key.PublicKey {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type nameServerAddresses struct {
+	host      string
+	addresses []string
+	err       error
+}
+
+// parityCheckNameServers queries the listed records on the current and the
+// planned name servers and reports, in a single diagnostic, every planned
+// server whose answers differ from the current ones. Entries whose values are
+// not known yet are skipped.
+func parityCheckNameServers(ctx context.Context, dns *dnsclient.Client, serviceName string, current, planned []CDCNameServersModel, records []CDCParityCheckModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	currentServers := lookupNameServers(ctx, dns, current)
+	plannedServers := lookupNameServers(ctx, dns, planned)
+
+	var mismatches []string
+	for _, record := range records {
+		if record.Name.IsUnknown() || record.Type.IsUnknown() {
+			continue
+		}
+
+		name := parityCheckName(serviceName, record.Name.ValueString())
+		qtype, err := dnsclient.ParseType(record.Type.ValueString())
+		if err != nil {
+			mismatches = append(mismatches, err.Error())
+			continue
+		}
+		label := name + " " + strings.ToUpper(record.Type.ValueString())
+
+		expected, err := firstAnswers(ctx, dns, currentServers, name, qtype)
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("%s: could not query current name servers: %s", label, err.Error()))
+			continue
+		}
+
+		for _, server := range plannedServers {
+			if server.err != nil {
+				mismatches = append(mismatches, fmt.Sprintf("%s: %s", label, server.err.Error()))
+				continue
+			}
+
+			for _, address := range server.addresses {
+				answers, err := dns.Answers(ctx, address, name, qtype)
+				if err != nil {
+					mismatches = append(mismatches, fmt.Sprintf("%s: %s", label, err.Error()))
+					continue
+				}
+
+				if strings.Join(answers, "\n") != strings.Join(expected, "\n") {
+					mismatches = append(mismatches, fmt.Sprintf(
+						"%s: %s (%s) answers [%s], current name servers answer [%s]",
+						label, server.host, address, strings.Join(answers, "; "), strings.Join(expected, "; "),
+					))
+				}
+			}
+		}
+	}
+
+	if len(mismatches) > 0 {
+		diags.AddAttributeError(
+			path.Root("parity_check"),
+			"Name server parity check failed",
+			"Planned name servers do not answer like the current ones:\n"+strings.Join(mismatches, "\n"),
+		)
+	}
+
+	return diags
+}
+
+// lookupNameServers finds the addresses of every name server whose values
+// are known.
+func lookupNameServers(ctx context.Context, dns *dnsclient.Client, nameServers []CDCNameServersModel) []nameServerAddresses {
+	servers := []nameServerAddresses{}
+
+	for _, nameServer := range nameServers {
+		if nameServer.Host.IsUnknown() || nameServer.IP.IsUnknown() {
+			continue
+		}
+
+		host := nameServer.Host.Canonical()
+		addresses, err := dns.ServerAddresses(ctx, host, nameServer.IP.ValueString())
+		servers = append(servers, nameServerAddresses{host: host, addresses: addresses, err: err})
+	}
+
+	return servers
+}
+
+// firstAnswers returns the answers of the first server able to reply.
+func firstAnswers(ctx context.Context, dns *dnsclient.Client, servers []nameServerAddresses, name string, qtype uint16) ([]string, error) {
+	lastErr := fmt.Errorf("no name server to query")
+
+	for _, server := range servers {
+		if server.err != nil {
+			lastErr = server.err
+			continue
+		}
+
+		for _, address := range server.addresses {
+			answers, err := dns.Answers(ctx, address, name, qtype)
+			if err == nil {
+				return answers, nil
+			}
+			lastErr = err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// parityCheckName qualifies a record name of the parity check with the
+// domain. "@" stands for the domain itself.
+func parityCheckName(serviceName string, name string) string {
+	name = dnsclient.CanonicalHost(name)
+	serviceName = dnsclient.CanonicalHost(serviceName)
+
+	switch {
+	case name == "@" || name == "":
+		return serviceName
+	case name == serviceName || strings.HasSuffix(name, "."+serviceName):
+		return name
+	}
+
+	return name + "." + serviceName
+}
+
+// waitForPropagation polls the parent zone servers until all of them delegate
+// serviceName to exactly the given name servers, or the timeout passes.
+func waitForPropagation(ctx context.Context, dns *dnsclient.Client, serviceName string, nameServers []CDCNameServersModel, config *CDCWaitForPropagationModel) error {
+	timeout, err := parseDuration(config.Timeout, defaultPropagationTimeout)
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseDuration(config.Interval, defaultPropagationInterval)
+	if err != nil {
+		return err
+	}
+
+	var parentServers []string
+	if diags := config.ParentServers.ElementsAs(ctx, &parentServers, false); diags.HasError() {
+		return fmt.Errorf("could not read parent_servers")
+	}
+
+	if len(parentServers) == 0 {
+		_, parentServers, err = dns.ParentZone(ctx, serviceName)
+		if err != nil {
+			return err
+		}
+	}
+
+	var addresses []string
+	for _, server := range parentServers {
+		serverAddresses, err := dns.ServerAddresses(ctx, server, "")
+		if err != nil {
+			return err
+		}
+		addresses = append(addresses, serverAddresses...)
+	}
+
+	expected := make([]string, 0, len(nameServers))
+	for _, nameServer := range nameServers {
+		expected = append(expected, nameServer.Host.Canonical())
+	}
+	sort.Strings(expected)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var pending []string
+		for _, address := range addresses {
+			hosts, _, err := dns.Delegation(ctx, address, serviceName)
+			if err != nil {
+				pending = append(pending, err.Error())
+				continue
+			}
+
+			sort.Strings(hosts)
+			if strings.Join(hosts, ",") != strings.Join(expected, ",") {
+				pending = append(pending, fmt.Sprintf("%s delegates to %s", address, strings.Join(hosts, ", ")))
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("delegation of %s did not match %s after %s:\n%s", serviceName, strings.Join(expected, ", "), timeout, strings.Join(pending, "\n"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// parseDuration parses a duration attribute, returning fallback when it is
+// not set.
+func parseDuration(value types.String, fallback time.Duration) (time.Duration, error) {
+	if value.IsNull() || value.IsUnknown() {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		return 0, err
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %s", value.ValueString())
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+)
+
+func TestDSRecordsMatch(t *testing.T) {
+	key := dnsclient.DNSKey{Flags: 257, Protocol: 3, Algorithm: 13, KeyTag: 2371, PublicKey: "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ=="}
+
+	testCases := map[string]struct {
+		dsRecords []api.DSRecord
+		want      bool
+	}{
+		"same key": {
+			dsRecords: []api.DSRecord{{Algorithm: 13, KeyTag: 2371, PublicKey: key.PublicKey}},
+			want:      true,
+		},
+		"public key split by spaces": {
+			dsRecords: []api.DSRecord{{Algorithm: 13, KeyTag: 2371, PublicKey: "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+ KkxLbxILfDLUT0rAK9iUzy1L53eKGQ=="}},
+			want:      true,
+		},
+		"no public key from OVH": {
+			dsRecords: []api.DSRecord{{Algorithm: 13, KeyTag: 2371}},
+			want:      true,
+		},
+		"one of several": {
+			dsRecords: []api.DSRecord{{Algorithm: 8, KeyTag: 20326}, {Algorithm: 13, KeyTag: 2371}},
+			want:      true,
+		},
+		"other key tag": {
+			dsRecords: []api.DSRecord{{Algorithm: 13, KeyTag: 2372}},
+			want:      false,
+		},
+		"other algorithm": {
+			dsRecords: []api.DSRecord{{Algorithm: 8, KeyTag: 2371}},
+			want:      false,
+		},
+		"other public key": {
+			dsRecords: []api.DSRecord{{Algorithm: 13, KeyTag: 2371, PublicKey: "AwEAAag="}},
+			want:      false,
+		},
+		"no DS records": {
+			dsRecords: []api.DSRecord{},
+			want:      false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := dsRecordsMatch(testCase.dsRecords, []dnsclient.DNSKey{key}); got != testCase.want {
+				t.Errorf("dsRecordsMatch(%+v) = %t, want %t", testCase.dsRecords, got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type CDCMigrationModel struct {
+	SoakTime types.String `tfsdk:"soak_time"`
+}
+
+// migrateNameServers publishes the current and planned name servers together
+// and waits for the soak time, so resolvers caching the old delegation still
+// reach a server with the zone. It returns false when the update must stop,
+// after setting the diagnostics and the state.
+func (r *CDCOvhNSResource) migrateNameServers(ctx context.Context, serviceName api.DomainName, plan *CDCOvhNSResourceModel, state *CDCOvhNSResourceModel, resp *resource.UpdateResponse) bool {
+	union := unionNameServers(state.NameServers, plan.NameServers)
+
+	// Only new servers are added, the old ones keep answering anyway.
+	if !nameServersChanged(union, plan.NameServers) {
+		return true
+	}
+
+	soakTime, err := migrationSoakTime(ctx, r.dns, serviceName.String(), plan.Migration)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error migrating name servers",
+			"UPDATE: Could not determine the migration soak time, set migration.soak_time: "+err.Error(),
+		)
+		return false
+	}
+
+	if nameServersChanged(union, state.NameServers) {
+		lastTask, err := r.applyNameServers(ctx, serviceName, union, resp.Private)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error migrating name servers",
+				"UPDATE: Could not publish current and new name servers together, unexpected error: "+err.Error(),
+			)
+			state.LastTask = lastTask
+			if plan.RollbackOnFailure.ValueBool() && ctx.Err() == nil && !lastTask.IsNull() {
+				r.rollbackNameServers(ctx, serviceName, state, resp)
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return false
+		}
+		plan.LastTask = lastTask
+	}
+
+	if plan.WaitForPropagation != nil {
+		if err := waitForPropagation(ctx, r.dns, serviceName.String(), union, plan.WaitForPropagation); err != nil {
+			resp.Diagnostics.AddError(
+				"Name servers not propagated",
+				"UPDATE: Current and new name servers were published but the parent zone does not delegate to them yet: "+err.Error(),
+			)
+			state.LastTask = plan.LastTask
+			if plan.RollbackOnFailure.ValueBool() && ctx.Err() == nil {
+				r.rollbackNameServers(ctx, serviceName, state, resp)
+			} else {
+				r.setMigrationState(serviceName, state, union)
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return false
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		resp.Diagnostics.AddError(
+			"Interrupted while migrating name servers",
+			fmt.Sprintf("UPDATE: Current and new name servers of %s are published together, the next apply will finish the migration", serviceName),
+		)
+		state.LastTask = plan.LastTask
+		r.setMigrationState(serviceName, state, union)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return false
+	case <-time.After(soakTime):
+	}
+
+	return true
+}
+
+// migrationRulesDiagnostics checks the name servers published together by the
+// first step of a migration against the registry rules, so a union refused by
+// the registry is reported when planning instead of in the middle of the
+// migration.
+func migrationRulesDiagnostics(serviceName HostnameValue, current, planned []CDCNameServersModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	union := unionNameServers(current, planned)
+	if !nameServersChanged(union, planned) {
+		return diags
+	}
+
+	for _, diagnostic := range validateTLDRules(serviceName, union) {
+		diags.AddAttributeError(
+			path.Root("migration"),
+			"Migration refused by registry rules",
+			"The first migration step publishes current and new name servers together: "+diagnostic.Detail()+
+				". Remove current name servers in a separate apply first, or change name servers without migration.",
+		)
+	}
+
+	return diags
+}
+
+// migrationSoakDiagnostics reports when planning how long the apply will keep
+// both name server sets published, or why the soak time cannot be found.
+func migrationSoakDiagnostics(ctx context.Context, dns *dnsclient.Client, serviceName string, current, planned []CDCNameServersModel, config *CDCMigrationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !nameServersChanged(unionNameServers(current, planned), planned) {
+		return diags
+	}
+
+	soakTime, err := migrationSoakTime(ctx, dns, serviceName, config)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("migration").AtName("soak_time"),
+			"Unknown migration soak time",
+			"Could not determine the migration soak time, set migration.soak_time: "+err.Error(),
+		)
+		return diags
+	}
+
+	diags.AddAttributeWarning(
+		path.Root("migration"),
+		"Migration soak time",
+		fmt.Sprintf("The apply will keep current and new name servers of %s published together for %s before publishing only the new ones. "+
+			"Set migration.soak_time to wait for another duration", serviceName, soakTime),
+	)
+
+	return diags
+}
+
+// setMigrationState records the name servers published by the first step of
+// a migration, read back from the API when possible.
+func (r *CDCOvhNSResource) setMigrationState(serviceName api.DomainName, state *CDCOvhNSResourceModel, union []CDCNameServersModel) {
+	nameServers, err := r.client.GetNameServersFromAPI(serviceName)
+	if err != nil {
+		state.NameServers = union
+		setComputedNameServers(state.NameServers, nil)
+		return
+	}
+
+	state.NameServers = convertReponseToResourceNS(nameServers, union)
+}
+
+// unionNameServers returns the planned name servers followed by the current
+// ones whose host is not planned.
+func unionNameServers(current, planned []CDCNameServersModel) []CDCNameServersModel {
+	union := append([]CDCNameServersModel{}, planned...)
+
+	for _, nameServer := range current {
+		found := false
+		for _, plannedNameServer := range planned {
+			if plannedNameServer.Host.SemanticallyEqual(nameServer.Host) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			union = append(union, nameServer)
+		}
+	}
+
+	return union
+}
+
+// migrationSoakTime returns the configured soak time, or the highest TTL of
+// the delegation of serviceName served by the parent zone.
+func migrationSoakTime(ctx context.Context, dns *dnsclient.Client, serviceName string, config *CDCMigrationModel) (time.Duration, error) {
+	if !config.SoakTime.IsNull() && !config.SoakTime.IsUnknown() {
+		return parseDuration(config.SoakTime, 0)
+	}
+
+	_, parentServers, err := dns.ParentZone(ctx, serviceName)
+	if err != nil {
+		return 0, err
+	}
+
+	var ttl uint32
+	var lastErr error
+	for _, server := range parentServers {
+		addresses, err := dns.ServerAddresses(ctx, server, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for _, address := range addresses {
+			_, delegationTTL, err := dns.Delegation(ctx, address, serviceName)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			if delegationTTL > ttl {
+				ttl = delegationTTL
+			}
+		}
+	}
+
+	if ttl == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("parent zone of %s returned no delegation TTL", serviceName)
+		}
+		return 0, lastErr
+	}
+
+	return time.Duration(ttl) * time.Second, nil
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/miekg/dns"
+)
+
+func TestMigrationRulesDiagnostics(t *testing.T) {
+	testCases := map[string]struct {
+		serviceName string
+		current     []CDCNameServersModel
+		planned     []CDCNameServersModel
+		wantErrors  int
+	}{
+		"union within default rule": {
+			serviceName: "example.com",
+			current:     []CDCNameServersModel{nameServer("ns1.old.net", ""), nameServer("ns2.old.net", ""), nameServer("ns3.old.net", "")},
+			planned:     []CDCNameServersModel{nameServer("ns1.new.net", ""), nameServer("ns2.new.net", ""), nameServer("ns3.new.net", "")},
+		},
+		"union above de maximum": {
+			serviceName: "example.de",
+			current:     []CDCNameServersModel{nameServer("ns1.old.net", ""), nameServer("ns2.old.net", ""), nameServer("ns3.old.net", "")},
+			planned:     []CDCNameServersModel{nameServer("ns1.new.net", ""), nameServer("ns2.new.net", ""), nameServer("ns3.new.net", "")},
+			wantErrors:  1,
+		},
+		"union sharing a glue ip for it": {
+			serviceName: "example.it",
+			current:     []CDCNameServersModel{nameServer("ns1.example.it", "192.0.2.1"), nameServer("ns2.example.it", "192.0.2.2")},
+			planned:     []CDCNameServersModel{nameServer("ns3.example.it", "192.0.2.1"), nameServer("ns4.example.it", "192.0.2.4")},
+			wantErrors:  1,
+		},
+		"only additions publish no union": {
+			serviceName: "example.de",
+			current:     []CDCNameServersModel{nameServer("ns1.old.net", ""), nameServer("ns2.old.net", "")},
+			planned:     []CDCNameServersModel{nameServer("ns1.old.net", ""), nameServer("ns2.old.net", ""), nameServer("ns1.new.net", "")},
+		},
+		"same host in other notation": {
+			serviceName: "example.de",
+			current:     []CDCNameServersModel{nameServer("NS1.old.net.", ""), nameServer("ns2.old.net", ""), nameServer("ns3.old.net", "")},
+			planned:     []CDCNameServersModel{nameServer("ns1.old.net", ""), nameServer("ns2.new.net", ""), nameServer("ns3.new.net", "")},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := migrationRulesDiagnostics(NewHostnameValue(testCase.serviceName), testCase.current, testCase.planned)
+
+			if diags.ErrorsCount() != testCase.wantErrors {
+				t.Errorf("got %d errors, want %d: %v", diags.ErrorsCount(), testCase.wantErrors, diags)
+			}
+		})
+	}
+}
+
+func TestMigrationSoakTime(t *testing.T) {
+	// A single server acts as resolver, parent zone and name servers.
+	address := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		question := req.Question[0]
+		switch {
+		case question.Qtype == dns.TypeNS && question.Name == "com.":
+			resp.Answer = append(resp.Answer, &dns.NS{Hdr: dns.RR_Header{Name: "com.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 172800}, Ns: "a.gtld-servers.net."})
+		case question.Qtype == dns.TypeA && question.Name == "a.gtld-servers.net.":
+			resp.Answer = append(resp.Answer, &dns.A{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300}, A: net.ParseIP("127.0.0.1")})
+		case question.Qtype == dns.TypeNS && question.Name == "example.com.":
+			for _, host := range []string{"ns1.old.net.", "ns2.old.net."} {
+				resp.Ns = append(resp.Ns, &dns.NS{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 172800}, Ns: host})
+			}
+		case question.Qtype == dns.TypeNS:
+			resp.Rcode = dns.RcodeNameError
+		}
+
+		w.WriteMsg(resp)
+	}))
+
+	_, port, _ := net.SplitHostPort(address)
+	portNumber, _ := strconv.Atoi(port)
+	client, err := dnsclient.NewClient(dnsclient.Config{Resolver: address, Port: portNumber, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewClient returned %q", err)
+	}
+
+	testCases := map[string]struct {
+		serviceName string
+		soakTime    types.String
+		want        time.Duration
+		wantErr     bool
+	}{
+		"configured":                 {serviceName: "example.com", soakTime: types.StringValue("90m"), want: 90 * time.Minute},
+		"delegation TTL of two days": {serviceName: "example.com", soakTime: types.StringNull(), want: 48 * time.Hour},
+		"invalid configured value":   {serviceName: "example.com", soakTime: types.StringValue("soon"), wantErr: true},
+		"no delegation":              {serviceName: "example.org", soakTime: types.StringNull(), wantErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := migrationSoakTime(context.Background(), client, testCase.serviceName, &CDCMigrationModel{SoakTime: testCase.soakTime})
+
+			if testCase.wantErr {
+				if err == nil {
+					t.Errorf("migrationSoakTime returned %s, want an error", got)
+				}
+				return
+			}
+
+			if err != nil || got != testCase.want {
+				t.Errorf("migrationSoakTime = %s, %v, want %s", got, err, testCase.want)
+			}
+		})
+	}
+}
+
+// startTestDNSServer serves handler over UDP on a random local port until the
+// test ends, and returns its host:port.
+func startTestDNSServer(t *testing.T, handler dns.Handler) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %s", err)
+	}
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: conn, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+	<-started
+
+	return conn.LocalAddr().String()
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// pendingTaskPrivateKey holds the OVH task an apply is waiting for. When the
+// apply is interrupted, the next plan and apply recognise the task as their
+// own instead of a foreign blocking task. The key is only saved when Update
+// returns, so the task of a killed provider stays a foreign one until it ends.
+const pendingTaskPrivateKey = "pending_task"
+
+type pendingTask struct {
+	ID int64 `json:"id,omitempty"`
+}
+
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getPendingTask returns the stored task ID, or 0 when there is none.
+func getPendingTask(ctx context.Context, private privateStateGetter) (int64, diag.Diagnostics) {
+	var task pendingTask
+
+	value, diags := private.GetKey(ctx, pendingTaskPrivateKey)
+	if diags.HasError() || len(value) == 0 {
+		return 0, diags
+	}
+
+	if err := json.Unmarshal(value, &task); err != nil {
+		diags.AddWarning(
+			"Unreadable pending task",
+			"Could not read the OVH task stored by a previous apply: "+err.Error(),
+		)
+		return 0, diags
+	}
+
+	return task.ID, diags
+}
+
+// setPendingTask stores the task ID, 0 clears it.
+func setPendingTask(ctx context.Context, private privateStateSetter, id int64) diag.Diagnostics {
+	value, err := json.Marshal(pendingTask{ID: id})
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Could not store pending task", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, pendingTaskPrivateKey, value)
+}
@@ -3,19 +3,18 @@ package provider
 import (
 	"context"
 	"fmt"
-	"net"
-	"strings"
+	"time"
 
 	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
-	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -23,6 +22,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CDCOvhNSResource{}
 var _ resource.ResourceWithImportState = &CDCOvhNSResource{}
+var _ resource.ResourceWithUpgradeState = &CDCOvhNSResource{}
 
 func NewCDCOvhNSResource() resource.Resource {
 	return &CDCOvhNSResource{}
@@ -30,20 +30,41 @@ func NewCDCOvhNSResource() resource.Resource {
 
 type CDCOvhNSResource struct {
 	client *api.APIClient
+	dns    *dnsclient.Client
 }
 
 type CDCOvhNSResourceModel struct {
-	ServiceName types.String                   `tfsdk:"service_name"`
-	Type        types.String                   `tfsdk:"type"`
-	NameServers map[string]CDCNameServersModel `tfsdk:"name_servers"`
+	ServiceName    HostnameValue         `tfsdk:"service_name"`
+	Type           types.String          `tfsdk:"type"`
+	NameServers    []CDCNameServersModel `tfsdk:"name_servers"`
+	PreflightCheck types.Bool            `tfsdk:"preflight_check"`
+
+	WaitForPropagation  *CDCWaitForPropagationModel `tfsdk:"wait_for_propagation"`
+	PropagatedAt        types.String                `tfsdk:"propagated_at"`
+	LastTask            types.Object                `tfsdk:"last_task"`
+	RollbackOnFailure   types.Bool                  `tfsdk:"rollback_on_failure"`
+	Migration           *CDCMigrationModel          `tfsdk:"migration"`
+	ParityCheck         []CDCParityCheckModel       `tfsdk:"parity_check"`
+	AllowDNSSECMismatch types.Bool                  `tfsdk:"allow_dnssec_mismatch"`
+}
+
+type CDCParityCheckModel struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+type CDCWaitForPropagationModel struct {
+	Timeout       types.String `tfsdk:"timeout"`
+	Interval      types.String `tfsdk:"interval"`
+	ParentServers types.List   `tfsdk:"parent_servers"`
 }
 
 type CDCNameServersModel struct {
-	ID       types.Int64  `tfsdk:"id"`
-	Host     types.String `tfsdk:"host"`
-	IP       types.String `tfsdk:"ip"`
-	IsUsed   types.Bool   `tfsdk:"is_used"`
-	ToDelete types.Bool   `tfsdk:"to_delete"`
+	ID       types.Int64   `tfsdk:"id"`
+	Host     HostnameValue `tfsdk:"host"`
+	IP       types.String  `tfsdk:"ip"`
+	IsUsed   types.Bool    `tfsdk:"is_used"`
+	ToDelete types.Bool    `tfsdk:"to_delete"`
 }
 
 func (r *CDCOvhNSResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -53,13 +74,15 @@ func (r *CDCOvhNSResource) Metadata(ctx context.Context, req resource.MetadataRe
 func (r *CDCOvhNSResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "OVH Name Server resource",
+		Version:     1,
 
 		Attributes: map[string]schema.Attribute{
 			"service_name": schema.StringAttribute{
 				MarkdownDescription: "Domain name",
 				Required:            true,
+				CustomType:          HostnameType{},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					hostnameRequiresReplace(),
 				},
 			},
 			"type": schema.StringAttribute{
@@ -67,45 +90,131 @@ func (r *CDCOvhNSResource) Schema(ctx context.Context, req resource.SchemaReques
 				Description: "OVH Name Servers type - 'external' (if external name servers like Cloudflare) or 'hosted' if OVH Name Servers.",
 				Default:     stringdefault.StaticString("external"),
 			},
-			"name_servers": schema.MapNestedAttribute{
-				Required: true,
-				Validators: []validator.Map{
-
-					mapvalidator.SizeAtLeast(2),
+			"name_servers": schema.SetNestedAttribute{
+				Required:    true,
+				Description: "Name servers of the domain, identified by host.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(2),
 				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"id": schema.Int64Attribute{
 							Computed: true,
-							PlanModifiers: []planmodifier.Int64{
-								int64planmodifier.UseStateForUnknown(),
-							},
 						},
 						"host": schema.StringAttribute{
 							Required:    true,
-							Description: "DNS Hostname",
+							CustomType:  HostnameType{},
+							Description: "DNS Hostname (RFC 1123)",
 						},
 						"ip": schema.StringAttribute{
 							Optional:    true,
 							Computed:    true,
-							Description: "DNS IP address",
+							Description: "Glue IPv4 or IPv6 address. Required when host is inside the domain, not allowed otherwise.",
 							Default:     stringdefault.StaticString(""),
 						},
 						"is_used": schema.BoolAttribute{
 							Computed: true,
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"to_delete": schema.BoolAttribute{
 							Computed: true,
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
 						},
 					},
 				},
 			},
+			"preflight_check": schema.BoolAttribute{
+				Optional: true,
+				Description: "Query every planned name server over DNS before changing the delegation and refuse the change " +
+					"if any of them does not answer authoritatively with the domain SOA and NS records.",
+			},
+			"parity_check": schema.ListNestedAttribute{
+				Optional: true,
+				Description: "Records queried on the current and the planned name servers before changing the delegation. " +
+					"The change is refused if any planned name server answers differently.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Record name, relative to the domain or fully qualified. '@' stands for the domain itself.",
+						},
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "Record type, eg: SOA, MX, TXT, A",
+						},
+					},
+				},
+			},
+			"allow_dnssec_mismatch": schema.BoolAttribute{
+				Optional: true,
+				Description: "Change name servers even when DS records are published for the domain and the planned name servers " +
+					"serve no matching DNSKEY. The check is then only reported as a warning.",
+			},
+			"wait_for_propagation": schema.SingleNestedAttribute{
+				Optional: true,
+				Description: "After the OVH task is done, wait until the authoritative servers of the parent zone " +
+					"delegate the domain to the configured name servers.",
+				Attributes: map[string]schema.Attribute{
+					"timeout": schema.StringAttribute{
+						Optional:    true,
+						Description: "How long to wait for the delegation, as a duration string. Defaults to 1h.",
+					},
+					"interval": schema.StringAttribute{
+						Optional:    true,
+						Description: "Delay between checks, as a duration string. Defaults to 30s.",
+					},
+					"parent_servers": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Parent zone name servers (host names or IP addresses) to query. Discovered over DNS when not set.",
+					},
+				},
+			},
+			"propagated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "RFC 3339 time at which the parent zone was seen delegating to the configured name servers.",
+			},
+			"rollback_on_failure": schema.BoolAttribute{
+				Optional: true,
+				Description: "When the update task ends in error or cancelled state, or the propagation check fails, " +
+					"send the previous name servers again and wait for them.",
+			},
+			"migration": schema.SingleNestedAttribute{
+				Optional: true,
+				Description: "Change name servers in two steps: publish the current and new name servers together, " +
+					"wait for the soak time, then publish only the new ones. Each step waits for its OVH task.",
+				Attributes: map[string]schema.Attribute{
+					"soak_time": schema.StringAttribute{
+						Optional: true,
+						Description: "How long both name server sets stay published, as a duration string. " +
+							"Defaults to the TTL of the delegation in the parent zone, often one or two days. The duration is shown when planning.",
+					},
+				},
+			},
+			"last_task": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "Last OVH task started by Terraform for this domain, recorded even when the task failed.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.Int64Attribute{
+						Computed:    true,
+						Description: "OVH task ID",
+					},
+					"function": schema.StringAttribute{
+						Computed:    true,
+						Description: "OVH task function, eg: DomainDnsUpdate",
+					},
+					"status": schema.StringAttribute{
+						Computed:    true,
+						Description: "Final task status",
+					},
+					"created": schema.StringAttribute{
+						Computed:    true,
+						Description: "Task creation date",
+					},
+					"finished": schema.StringAttribute{
+						Computed:    true,
+						Description: "Task completion date, or last update date of a failed task",
+					},
+				},
+			},
 		},
 	}
 }
@@ -125,15 +234,55 @@ func (r CDCOvhNSResource) ValidateConfig(ctx context.Context, req resource.Valid
 		)
 	}
 
-	for key, NameServer := range data.NameServers {
-		if (strings.Trim(NameServer.IP.ValueString(), `"`) != "" || !NameServer.IP.IsNull()) && net.ParseIP(NameServer.IP.ValueString()) == nil {
+	if data.WaitForPropagation != nil {
+		if _, err := parseDuration(data.WaitForPropagation.Timeout, defaultPropagationTimeout); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("wait_for_propagation").AtName("timeout"),
+				"Wrong timeout",
+				err.Error(),
+			)
+		}
+
+		if _, err := parseDuration(data.WaitForPropagation.Interval, defaultPropagationInterval); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("wait_for_propagation").AtName("interval"),
+				"Wrong interval",
+				err.Error(),
+			)
+		}
+	}
+
+	if data.Migration != nil {
+		if _, err := parseDuration(data.Migration.SoakTime, 0); err != nil {
 			resp.Diagnostics.AddAttributeError(
-				path.Root("name_servers").AtMapKey(key),
-				"IP is not valid",
-				"Provide real ip address",
+				path.Root("migration").AtName("soak_time"),
+				"Wrong soak time",
+				err.Error(),
 			)
 		}
 	}
+
+	for i, record := range data.ParityCheck {
+		if record.Type.IsNull() || record.Type.IsUnknown() {
+			continue
+		}
+
+		if _, err := dnsclient.ParseType(record.Type.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("parity_check").AtListIndex(i).AtName("type"),
+				"Wrong record type",
+				err.Error(),
+			)
+		}
+	}
+
+	if !data.ServiceName.IsNull() && !data.ServiceName.IsUnknown() {
+		_, diags := parseServiceName(data.ServiceName)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	resp.Diagnostics.Append(validateNameServers(data.ServiceName, data.NameServers)...)
+	resp.Diagnostics.Append(validateTLDRules(data.ServiceName, data.NameServers)...)
 }
 
 func (r *CDCOvhNSResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -141,23 +290,25 @@ func (r *CDCOvhNSResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*api.APIClient)
+	providerData, ok := req.ProviderData.(*CDCOvhNSProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *api.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.CDCOvhNSProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Client
+	r.dns = providerData.DNS
 }
 
 func (r *CDCOvhNSResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
 	var plan, state *CDCOvhNSResourceModel
-	var serviceName string
+	var serviceName api.DomainName
+	var diags diag.Diagnostics
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -171,15 +322,43 @@ func (r *CDCOvhNSResource) ModifyPlan(ctx context.Context, req resource.ModifyPl
 				"See documentantion for more information",
 			),
 		)
+		return
 	}
 
 	if plan != nil {
-		serviceName = plan.ServiceName.ValueString()
+		serviceName, diags = parseServiceName(plan.ServiceName)
 	} else {
-		serviceName = state.ServiceName.ValueString()
+		serviceName, diags = parseServiceName(state.ServiceName)
+	}
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	currentTasks := r.client.CheckCurrentTaskState(serviceName)
+	if plan != nil {
+		exists, err := r.client.DomainExists(serviceName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading domains",
+				"Could not list the domains of the OVH account, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		if !exists {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("service_name"),
+				"Domain not found",
+				fmt.Sprintf("Domain %s is not in the OVH account of the configured credentials", serviceName),
+			)
+			return
+		}
+	}
+
+	pendingTaskID, diags := getPendingTask(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+
+	currentTasks := r.client.CheckCurrentTaskState(serviceName, pendingTaskID)
 	if currentTasks != nil {
 		resp.Diagnostics.AddError(
 			"Some task are already in operation",
@@ -188,6 +367,73 @@ func (r *CDCOvhNSResource) ModifyPlan(ctx context.Context, req resource.ModifyPl
 		return
 	}
 
+	if pendingTaskID != 0 && plan != nil {
+		resp.Diagnostics.AddWarning(
+			"Resuming interrupted OVH task",
+			fmt.Sprintf("Task %d started by an interrupted apply is still running, an update will wait for it before sending name servers again", pendingTaskID),
+		)
+	}
+
+	// Catch values that were still unknown during validation.
+	if plan != nil {
+		resp.Diagnostics.Append(validateTLDRules(plan.ServiceName, plan.NameServers)...)
+	}
+
+	if plan != nil && state != nil && (nameServersChanged(plan.NameServers, state.NameServers) || plan.Type.ValueString() != state.Type.ValueString()) {
+		domain, err := r.client.GetDomain(serviceName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading domain",
+				"Could not read domain state, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		resp.Diagnostics.Append(domainStateDiagnostics(domain, true)...)
+		resp.Diagnostics.Append(glueSupportDiagnostics(domain, plan.NameServers)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if plan != nil && state != nil && plan.PreflightCheck.ValueBool() && nameServersChanged(plan.NameServers, state.NameServers) {
+		resp.Diagnostics.Append(preflightCheckNameServers(ctx, r.dns, serviceName.String(), plan.NameServers)...)
+	}
+
+	if plan != nil && state != nil && nameServersChanged(plan.NameServers, state.NameServers) {
+		resp.Diagnostics.Append(dnssecCheckNameServers(ctx, r.client, r.dns, serviceName, plan.NameServers, plan.AllowDNSSECMismatch.ValueBool())...)
+	}
+
+	if plan != nil && state != nil && len(plan.ParityCheck) > 0 && nameServersChanged(plan.NameServers, state.NameServers) {
+		resp.Diagnostics.Append(parityCheckNameServers(ctx, r.dns, serviceName.String(), state.NameServers, plan.NameServers, plan.ParityCheck)...)
+	}
+
+	if plan != nil && state != nil && plan.Migration != nil && nameServersChanged(plan.NameServers, state.NameServers) {
+		resp.Diagnostics.Append(migrationRulesDiagnostics(plan.ServiceName, state.NameServers, plan.NameServers)...)
+		resp.Diagnostics.Append(migrationSoakDiagnostics(ctx, r.dns, serviceName.String(), state.NameServers, plan.NameServers, plan.Migration)...)
+	}
+
+	if plan != nil && state != nil {
+		// Resuming a recorded task sets last_task and may resend the name
+		// servers, even when the configuration did not change.
+		changed := nameServersChanged(plan.NameServers, state.NameServers) || pendingTaskID != 0
+		copyComputedNameServers(plan.NameServers, state.NameServers, changed)
+
+		if plan.WaitForPropagation != nil && changed {
+			plan.PropagatedAt = types.StringUnknown()
+		} else {
+			plan.PropagatedAt = state.PropagatedAt
+		}
+
+		if changed {
+			plan.LastTask = types.ObjectUnknown(lastTaskAttrTypes)
+		} else {
+			plan.LastTask = state.LastTask
+		}
+
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	}
+
 	// If the entire plan is null, the resource is planned for destruction.
 	if req.Plan.Raw.IsNull() {
 		resp.Diagnostics.AddWarning(
@@ -216,9 +462,47 @@ func (r *CDCOvhNSResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	serviceName := data.ServiceName.ValueString()
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	currentTasks := r.client.CheckCurrentTaskState(serviceName)
+	domain, err := r.client.GetDomain(serviceName)
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Domain not found",
+			fmt.Sprintf("READ: Domain %s is not in the OVH account anymore, it may have expired or been transferred out. "+
+				"Removing it from state, remove the resource from the configuration or import it again", serviceName),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Name Servers",
+			"READ: Could not read domain, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(domainStateDiagnostics(domain, false)...)
+
+	pendingTaskID, diags := getPendingTask(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+
+	// The task of an interrupted apply may have finished since.
+	if pendingTaskID != 0 {
+		task, err := r.client.GetOVHTask(serviceName, pendingTaskID)
+		if err == nil && task.Status != "todo" && task.Status != "doing" {
+			resp.Diagnostics.Append(setPendingTask(ctx, resp.Private, 0)...)
+			data.LastTask = lastTaskValue(task)
+			pendingTaskID = 0
+		}
+	}
+
+	currentTasks := r.client.CheckCurrentTaskState(serviceName, pendingTaskID)
 	if currentTasks != nil {
 		resp.Diagnostics.AddWarning(
 			"Some task already in operation",
@@ -227,26 +511,26 @@ func (r *CDCOvhNSResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	nameServers, err := r.client.GetNameServersFromAPI(serviceName)
-	nsTypeResponse, nsTypeErr := r.client.GetNameServersType(serviceName)
-
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading Name Servers",
-			"READ: Could not read current name servers, unexpected error: "+err.Error(),
+	if api.IsNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Domain not found",
+			fmt.Sprintf("READ: Name servers of %s are not in the OVH account anymore. "+
+				"Removing them from state, remove the resource from the configuration or import it again", serviceName),
 		)
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
-	if nsTypeErr != nil {
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Name Servers",
-			"READ: Could not read current name servers, unexpected error: "+nsTypeErr.Error(),
+			"READ: Could not read current name servers, unexpected error: "+err.Error(),
 		)
 		return
 	}
 
-	data.Type = types.StringValue(nsTypeResponse.NameServerType)
-	data.NameServers = convertReponseToResourceNS(nameServers)
+	data.Type = types.StringValue(domain.NameServerType)
+	data.NameServers = convertReponseToResourceNS(nameServers, data.NameServers)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -260,9 +544,16 @@ func (r *CDCOvhNSResource) Update(ctx context.Context, req resource.UpdateReques
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
-	serviceName := plan.ServiceName.ValueString()
+	serviceName, diags := parseServiceName(plan.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pendingTaskID, diags := getPendingTask(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
 
-	currentTasks := r.client.CheckCurrentTaskState(serviceName)
+	currentTasks := r.client.CheckCurrentTaskState(serviceName, pendingTaskID)
 	if currentTasks != nil {
 		resp.Diagnostics.AddError(
 			"Some task already in operation",
@@ -285,10 +576,122 @@ func (r *CDCOvhNSResource) Update(ctx context.Context, req resource.UpdateReques
 		}
 	}
 
+	// Only the host notation changed, nothing to send to OVH.
+	if !nameServersChanged(plan.NameServers, state.NameServers) && pendingTaskID == 0 {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	// A previous apply was interrupted while waiting for its own task.
+	if pendingTaskID != 0 {
+		waitErr := waitForTask(ctx, r.client, serviceName, pendingTaskID)
+		plan.LastTask = r.lastTask(serviceName, pendingTaskID)
+
+		if ctx.Err() != nil {
+			resp.Diagnostics.AddError(
+				"Interrupted while waiting for OVH task",
+				fmt.Sprintf("UPDATE: Task %d is still running, the next apply will resume waiting for it", pendingTaskID),
+			)
+			state.LastTask = plan.LastTask
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		}
+		resp.Diagnostics.Append(setPendingTask(ctx, resp.Private, 0)...)
+
+		if waitErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resumed OVH task failed",
+				fmt.Sprintf("UPDATE: Task %d started by an interrupted apply failed, name servers will be sent again: %s", pendingTaskID, waitErr.Error()),
+			)
+		} else if nameServers, err := r.client.GetNameServersFromAPI(serviceName); err == nil {
+			state.NameServers = convertReponseToResourceNS(nameServers, state.NameServers)
+		}
+	}
+
+	if nameServersChanged(plan.NameServers, state.NameServers) {
+		if plan.PreflightCheck.ValueBool() {
+			resp.Diagnostics.Append(preflightCheckNameServers(ctx, r.dns, serviceName.String(), plan.NameServers)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		// Warnings were already shown in the plan.
+		if diags := dnssecCheckNameServers(ctx, r.client, r.dns, serviceName, plan.NameServers, plan.AllowDNSSECMismatch.ValueBool()); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+
+		if len(plan.ParityCheck) > 0 {
+			resp.Diagnostics.Append(parityCheckNameServers(ctx, r.dns, serviceName.String(), state.NameServers, plan.NameServers, plan.ParityCheck)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		if plan.Migration != nil && !r.migrateNameServers(ctx, serviceName, plan, state, resp) {
+			return
+		}
+
+		lastTask, err := r.applyNameServers(ctx, serviceName, plan.NameServers, resp.Private)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating name servers",
+				"UPDATE: Could not update current name servers, unexpected error: "+err.Error(),
+			)
+			// Keep the previous name servers but record which task failed.
+			state.LastTask = lastTask
+			if plan.RollbackOnFailure.ValueBool() && ctx.Err() == nil && !lastTask.IsNull() {
+				r.rollbackNameServers(ctx, serviceName, state, resp)
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		}
+		plan.LastTask = lastTask
+	}
+
+	nameServers, err := r.client.GetNameServersFromAPI(serviceName)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Error reading Name Servers",
+			"UPDATE: Name servers were updated but could not be read back, run 'terraform refresh' later: "+err.Error(),
+		)
+	}
+	setComputedNameServers(plan.NameServers, nameServers)
+
+	if plan.WaitForPropagation != nil {
+		if err := waitForPropagation(ctx, r.dns, serviceName.String(), plan.NameServers, plan.WaitForPropagation); err != nil {
+			resp.Diagnostics.AddError(
+				"Name servers not propagated",
+				"UPDATE: Name servers were updated but the parent zone does not delegate to them yet: "+err.Error(),
+			)
+			if plan.RollbackOnFailure.ValueBool() && ctx.Err() == nil {
+				state.LastTask = plan.LastTask
+				r.rollbackNameServers(ctx, serviceName, state, resp)
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+				return
+			}
+			plan.PropagatedAt = types.StringNull()
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			return
+		}
+		plan.PropagatedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// applyNameServers sends the name server set to OVH and waits for the task.
+// The task ID is kept in private state while waiting, and left there when the
+// wait is interrupted so the next apply can resume it.
+func (r *CDCOvhNSResource) applyNameServers(ctx context.Context, serviceName api.DomainName, nameServers []CDCNameServersModel, private privateStateSetter) (types.Object, error) {
 	nameServerCreatePayloads := []*api.NameServerCreatePayload{}
-	for _, NameServer := range plan.NameServers {
+	for _, NameServer := range nameServers {
 		newNsPayload := &api.NameServerCreatePayload{
-			Host: NameServer.Host.ValueString(),
+			Host: NameServer.Host.Canonical(),
 			IP:   NameServer.IP.ValueString(),
 		}
 		nameServerCreatePayloads = append(nameServerCreatePayloads, newNsPayload)
@@ -300,27 +703,52 @@ func (r *CDCOvhNSResource) Update(ctx context.Context, req resource.UpdateReques
 
 	generatedApiTask, err := r.client.UpdateNameServers(serviceName, updatedNsData)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Name Servers",
-			"UPDATE_NS: Could not update current name servers, unexpected error: "+err.Error(),
-		)
-		return
+		return types.ObjectNull(lastTaskAttrTypes), err
+	}
+
+	if diags := setPendingTask(ctx, private, generatedApiTask.ID); diags.HasError() {
+		return types.ObjectNull(lastTaskAttrTypes), fmt.Errorf("could not store task %d in private state", generatedApiTask.ID)
 	}
 
 	// Wait for update in API
-	taskErr := make(chan error)
-	go r.client.CheckOVHTask(taskErr, generatedApiTask.ServiceName, generatedApiTask.ID)
-	if err := <-taskErr; err != nil {
+	waitErr := waitForTask(ctx, r.client, generatedApiTask.ServiceName, generatedApiTask.ID)
+	lastTask := r.lastTask(generatedApiTask.ServiceName, generatedApiTask.ID)
+
+	if ctx.Err() != nil {
+		return lastTask, fmt.Errorf("%w. Task %d is still running, the next apply will resume waiting for it", waitErr, generatedApiTask.ID)
+	}
+
+	if diags := setPendingTask(ctx, private, 0); diags.HasError() {
+		return lastTask, fmt.Errorf("could not clear task %d from private state", generatedApiTask.ID)
+	}
+
+	return lastTask, waitErr
+}
+
+// rollbackNameServers sends the name servers of state again after a failed
+// update and reports the outcome next to the original failure. The state is
+// refreshed from the API and records the rollback task.
+func (r *CDCOvhNSResource) rollbackNameServers(ctx context.Context, serviceName api.DomainName, state *CDCOvhNSResourceModel, resp *resource.UpdateResponse) {
+	lastTask, err := r.applyNameServers(ctx, serviceName, state.NameServers, resp.Private)
+	if !lastTask.IsNull() {
+		state.LastTask = lastTask
+	}
+
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error updating name servers",
-			"UPDATE: Could not update current name servers, unexpected error: "+err.Error(),
+			"Rollback failed",
+			"UPDATE: Could not restore the previous name servers, check OVH Panel: "+err.Error(),
 		)
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
+	resp.Diagnostics.AddWarning(
+		"Name servers rolled back",
+		fmt.Sprintf("UPDATE: Previous name servers of %s were restored after the failed update", serviceName),
+	)
+
+	if nameServers, err := r.client.GetNameServersFromAPI(serviceName); err == nil {
+		state.NameServers = convertReponseToResourceNS(nameServers, state.NameServers)
 	}
 }
 
@@ -333,7 +761,11 @@ func (r *CDCOvhNSResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	serviceName := data.ServiceName.ValueString()
+	serviceName, diags := parseServiceName(data.ServiceName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	currentTasks := r.client.CheckCurrentTaskState(serviceName)
 	if currentTasks != nil {
@@ -363,7 +795,14 @@ func (r *CDCOvhNSResource) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (r *CDCOvhNSResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	serviceName := req.ID
+	serviceName, err := api.ParseDomainName(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Wrong import ID",
+			"IMPORT: Expected a domain name: "+err.Error(),
+		)
+		return
+	}
 
 	currentTasks := r.client.CheckCurrentTaskState(serviceName)
 	if currentTasks != nil {
@@ -375,9 +814,7 @@ func (r *CDCOvhNSResource) ImportState(ctx context.Context, req resource.ImportS
 	}
 
 	nameServers, err := r.client.GetNameServersFromAPI(serviceName)
-	nsType, nsTypeErr := r.client.GetNameServersType(serviceName)
-
-	if err != nil || nsTypeErr != nil {
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading endpoint",
 			"IMPORT: Could not read current name servers, unexpected error: "+err.Error(),
@@ -385,22 +822,146 @@ func (r *CDCOvhNSResource) ImportState(ctx context.Context, req resource.ImportS
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), serviceName)...)
+	nsType, err := r.client.GetNameServersType(serviceName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading endpoint",
+			"IMPORT: Could not read current name servers type, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service_name"), req.ID)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), types.StringValue(nsType.NameServerType))...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name_servers"), convertReponseToResourceNS(nameServers))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name_servers"), convertReponseToResourceNS(nameServers, nil))...)
 }
 
-func convertReponseToResourceNS(nameServers map[string]api.NameServerOvhResponse) map[string]CDCNameServersModel {
-	resourceNameServers := make(map[string]CDCNameServersModel)
+var nameServerAttrTypes = map[string]attr.Type{
+	"id":        types.Int64Type,
+	"host":      HostnameType{},
+	"ip":        types.StringType,
+	"is_used":   types.BoolType,
+	"to_delete": types.BoolType,
+}
 
-	for key, data := range nameServers {
-		resourceNameServers[key] = CDCNameServersModel{
+// nameServerPath returns the path of a name_servers element, so diagnostics
+// point at the entry they are about.
+func nameServerPath(nameServer CDCNameServersModel) path.Path {
+	return path.Root("name_servers").AtSetValue(types.ObjectValueMust(nameServerAttrTypes, map[string]attr.Value{
+		"id":        nameServer.ID,
+		"host":      nameServer.Host,
+		"ip":        nameServer.IP,
+		"is_used":   nameServer.IsUsed,
+		"to_delete": nameServer.ToDelete,
+	}))
+}
+
+var lastTaskAttrTypes = map[string]attr.Type{
+	"id":       types.Int64Type,
+	"function": types.StringType,
+	"status":   types.StringType,
+	"created":  types.StringType,
+	"finished": types.StringType,
+}
+
+// lastTask reads the final state of an OVH task for the last_task attribute.
+// The task ID is kept even when the task cannot be read.
+func (r *CDCOvhNSResource) lastTask(serviceName api.DomainName, id int64) types.Object {
+	task, err := r.client.GetOVHTask(serviceName, id)
+	if err != nil {
+		task = api.DomainTask{ID: id}
+	}
+
+	return lastTaskValue(task)
+}
+
+func lastTaskValue(task api.DomainTask) types.Object {
+	finished := task.DoneDate
+	if finished == "" && (task.Status == "error" || task.Status == "cancelled") {
+		finished = task.LastUpdate
+	}
+
+	return types.ObjectValueMust(lastTaskAttrTypes, map[string]attr.Value{
+		"id":       types.Int64Value(task.ID),
+		"function": stringValueOrNull(task.Function),
+		"status":   stringValueOrNull(task.Status),
+		"created":  stringValueOrNull(task.CreationDate),
+		"finished": stringValueOrNull(finished),
+	})
+}
+
+func stringValueOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// convertReponseToResourceNS builds the name server set from the API. Hosts
+// and ips semantically equal to the prior ones keep their prior notation.
+func convertReponseToResourceNS(nameServers []api.NameServerOvhResponse, prior []CDCNameServersModel) []CDCNameServersModel {
+	resourceNameServers := []CDCNameServersModel{}
+
+	for _, data := range nameServers {
+		nameServer := CDCNameServersModel{
 			ID:       types.Int64Value(int64(data.Id)),
-			Host:     types.StringValue(data.GetHost()),
+			Host:     NewHostnameValue(data.GetHost()),
 			IP:       types.StringValue(data.GetIP()),
 			IsUsed:   types.BoolValue(data.IsUsed),
 			ToDelete: types.BoolValue(data.ToDelete),
 		}
+
+		for _, priorNameServer := range prior {
+			if priorNameServer.Host.SemanticallyEqual(nameServer.Host) {
+				nameServer.Host = priorNameServer.Host
+				if sameIP(priorNameServer.IP.ValueString(), nameServer.IP.ValueString()) {
+					nameServer.IP = priorNameServer.IP
+				}
+			}
+		}
+
+		resourceNameServers = append(resourceNameServers, nameServer)
 	}
 	return resourceNameServers
 }
+
+// copyComputedNameServers keeps the computed attributes of the name servers
+// unless changed is set. Any change resubmits the whole set to OVH, which
+// recreates every entry, so they are all marked unknown.
+func copyComputedNameServers(planned, current []CDCNameServersModel, changed bool) {
+	for i := range planned {
+		planned[i].ID = types.Int64Unknown()
+		planned[i].IsUsed = types.BoolUnknown()
+		planned[i].ToDelete = types.BoolUnknown()
+
+		if changed {
+			continue
+		}
+
+		for _, nameServer := range current {
+			if nameServer.Host.SemanticallyEqual(planned[i].Host) {
+				planned[i].ID = nameServer.ID
+				planned[i].IsUsed = nameServer.IsUsed
+				planned[i].ToDelete = nameServer.ToDelete
+			}
+		}
+	}
+}
+
+// setComputedNameServers fills the computed attributes of the name servers
+// from the API, matching them by host.
+func setComputedNameServers(planned []CDCNameServersModel, nameServers []api.NameServerOvhResponse) {
+	for i := range planned {
+		planned[i].ID = types.Int64Null()
+		planned[i].IsUsed = types.BoolNull()
+		planned[i].ToDelete = types.BoolNull()
+
+		for _, data := range nameServers {
+			if planned[i].Host.SemanticallyEqual(NewHostnameValue(data.GetHost())) {
+				planned[i].ID = types.Int64Value(int64(data.Id))
+				planned[i].IsUsed = types.BoolValue(data.IsUsed)
+				planned[i].ToDelete = types.BoolValue(data.ToDelete)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestNameServersServiceNameRequiresReplace(t *testing.T) {
+	testCases := map[string]struct {
+		state string
+		plan  string
+		want  bool
+	}{
+		"same name":            {state: "example.com", plan: "example.com", want: false},
+		"case and dot only":    {state: "Example.COM.", plan: "example.com", want: false},
+		"punycode to unicode":  {state: "xn--bcher-kva.example", plan: "bücher.example", want: false},
+		"other domain":         {state: "example.com", plan: "example.net", want: true},
+		"other domain and dot": {state: "example.com.", plan: "Example.NET", want: true},
+	}
+
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	NewCDCOvhNSResource().Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	attribute, ok := schemaResp.Schema.Attributes["service_name"].(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("service_name is a %T, want schema.StringAttribute", schemaResp.Schema.Attributes["service_name"])
+	}
+
+	// Only the raw values are checked by the plan modifiers, to tell creation
+	// and destruction apart from updates.
+	raw := tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := planmodifier.StringRequest{
+				Path:        path.Root("service_name"),
+				State:       tfsdk.State{Raw: raw},
+				Plan:        tfsdk.Plan{Raw: raw},
+				StateValue:  types.StringValue(testCase.state),
+				PlanValue:   types.StringValue(testCase.plan),
+				ConfigValue: types.StringValue(testCase.plan),
+			}
+
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+			for _, modifier := range attribute.PlanModifiers {
+				modifier.PlanModifyString(ctx, req, resp)
+			}
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if resp.RequiresReplace != testCase.want {
+				t.Errorf("RequiresReplace from %q to %q = %t, want %t", testCase.state, testCase.plan, resp.RequiresReplace, testCase.want)
+			}
+		})
+	}
+}
+
+func TestCopyComputedNameServers(t *testing.T) {
+	current := []CDCNameServersModel{
+		{Host: NewHostnameValue("dns1.provider.net"), IP: types.StringNull(), ID: types.Int64Value(1), IsUsed: types.BoolValue(true), ToDelete: types.BoolValue(false)},
+		{Host: NewHostnameValue("dns2.provider.net"), IP: types.StringNull(), ID: types.Int64Value(2), IsUsed: types.BoolValue(true), ToDelete: types.BoolValue(false)},
+	}
+
+	testCases := map[string]struct {
+		hosts     []string
+		changed   bool
+		wantKnown bool
+	}{
+		"unchanged":               {hosts: []string{"dns1.provider.net", "dns2.provider.net"}, wantKnown: true},
+		"other notation":          {hosts: []string{"DNS1.provider.net.", "dns2.provider.net"}, wantKnown: true},
+		"changed set":             {hosts: []string{"dns1.provider.net", "dns3.provider.net"}, changed: true},
+		"unchanged but resumed":   {hosts: []string{"dns1.provider.net", "dns2.provider.net"}, changed: true},
+		"host not in current set": {hosts: []string{"dns3.provider.net"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := []CDCNameServersModel{}
+			for _, host := range testCase.hosts {
+				planned = append(planned, nameServer(host, ""))
+			}
+
+			copyComputedNameServers(planned, current, testCase.changed)
+
+			for _, nameServer := range planned {
+				known := !nameServer.ID.IsUnknown() && !nameServer.IsUsed.IsUnknown() && !nameServer.ToDelete.IsUnknown()
+				if known != testCase.wantKnown {
+					t.Errorf("computed attributes of %s known = %t, want %t", nameServer.Host, known, testCase.wantKnown)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// CDCOvhNSResourceModelV0 is the state of schema version 0, where name servers
+// were a map with arbitrary keys.
+type CDCOvhNSResourceModelV0 struct {
+	ServiceName    HostnameValue                  `tfsdk:"service_name"`
+	Type           types.String                   `tfsdk:"type"`
+	NameServers    map[string]CDCNameServersModel `tfsdk:"name_servers"`
+	PreflightCheck types.Bool                     `tfsdk:"preflight_check"`
+
+	WaitForPropagation *CDCWaitForPropagationModel `tfsdk:"wait_for_propagation"`
+	PropagatedAt       types.String                `tfsdk:"propagated_at"`
+}
+
+func (r *CDCOvhNSResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"service_name": schema.StringAttribute{
+						Required:   true,
+						CustomType: HostnameType{},
+					},
+					"type": schema.StringAttribute{
+						Computed: true,
+					},
+					"name_servers": schema.MapNestedAttribute{
+						Required: true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.Int64Attribute{
+									Computed: true,
+								},
+								"host": schema.StringAttribute{
+									Required:   true,
+									CustomType: HostnameType{},
+								},
+								"ip": schema.StringAttribute{
+									Optional: true,
+									Computed: true,
+								},
+								"is_used": schema.BoolAttribute{
+									Computed: true,
+								},
+								"to_delete": schema.BoolAttribute{
+									Computed: true,
+								},
+							},
+						},
+					},
+					"preflight_check": schema.BoolAttribute{
+						Optional: true,
+					},
+					"wait_for_propagation": schema.SingleNestedAttribute{
+						Optional: true,
+						Attributes: map[string]schema.Attribute{
+							"timeout": schema.StringAttribute{
+								Optional: true,
+							},
+							"interval": schema.StringAttribute{
+								Optional: true,
+							},
+							"parent_servers": schema.ListAttribute{
+								Optional:    true,
+								ElementType: types.StringType,
+							},
+						},
+					},
+					"propagated_at": schema.StringAttribute{
+						Computed: true,
+					},
+				},
+			},
+			StateUpgrader: upgradeNameServersStateV0,
+		},
+	}
+}
+
+func upgradeNameServersStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior CDCOvhNSResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keys := make([]string, 0, len(prior.NameServers))
+	for key := range prior.NameServers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	nameServers := make([]CDCNameServersModel, 0, len(keys))
+	for _, key := range keys {
+		nameServers = append(nameServers, prior.NameServers[key])
+	}
+
+	upgraded := CDCOvhNSResourceModel{
+		ServiceName:        prior.ServiceName,
+		Type:               prior.Type,
+		NameServers:        nameServers,
+		PreflightCheck:     prior.PreflightCheck,
+		WaitForPropagation: prior.WaitForPropagation,
+		PropagatedAt:       prior.PropagatedAt,
+		LastTask:           types.ObjectNull(lastTaskAttrTypes),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
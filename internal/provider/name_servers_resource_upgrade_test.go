@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUpgradeNameServersStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &CDCOvhNSResource{}
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("no state upgrader for version 0")
+	}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+	nameServerType := priorType.AttributeTypes["name_servers"].(tftypes.Map).ElementType.(tftypes.Object)
+	waitType := priorType.AttributeTypes["wait_for_propagation"]
+
+	v0NameServer := func(id int64, host string, ip string) tftypes.Value {
+		return tftypes.NewValue(nameServerType, map[string]tftypes.Value{
+			"id":        tftypes.NewValue(tftypes.Number, id),
+			"host":      tftypes.NewValue(tftypes.String, host),
+			"ip":        tftypes.NewValue(tftypes.String, ip),
+			"is_used":   tftypes.NewValue(tftypes.Bool, true),
+			"to_delete": tftypes.NewValue(tftypes.Bool, false),
+		})
+	}
+
+	// Keys are given out of order, the upgraded set follows their sort order.
+	prior := tftypes.NewValue(priorType, map[string]tftypes.Value{
+		"service_name": tftypes.NewValue(tftypes.String, "example.com"),
+		"type":         tftypes.NewValue(tftypes.String, "external"),
+		"name_servers": tftypes.NewValue(priorType.AttributeTypes["name_servers"], map[string]tftypes.Value{
+			"ns3": v0NameServer(3, "ns.example.com", "192.0.2.1"),
+			"ns1": v0NameServer(1, "dns2.provider.net", ""),
+			"ns2": v0NameServer(2, "dns1.provider.net", ""),
+		}),
+		"preflight_check":      tftypes.NewValue(tftypes.Bool, nil),
+		"wait_for_propagation": tftypes.NewValue(waitType, nil),
+		"propagated_at":        tftypes.NewValue(tftypes.String, "2024-01-01T00:00:00Z"),
+	})
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: prior},
+	}
+	resp := &resource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	upgrader.StateUpgrader(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var upgraded CDCOvhNSResourceModel
+	if diags := resp.State.Get(ctx, &upgraded); diags.HasError() {
+		t.Fatalf("could not read upgraded state: %v", diags)
+	}
+
+	if upgraded.ServiceName.ValueString() != "example.com" || upgraded.Type.ValueString() != "external" {
+		t.Errorf("service_name and type = %s, %s, want example.com, external", upgraded.ServiceName, upgraded.Type)
+	}
+
+	if upgraded.PropagatedAt.ValueString() != "2024-01-01T00:00:00Z" {
+		t.Errorf("propagated_at = %s, want it kept", upgraded.PropagatedAt)
+	}
+
+	wantHosts := []string{"dns2.provider.net", "dns1.provider.net", "ns.example.com"}
+	if len(upgraded.NameServers) != len(wantHosts) {
+		t.Fatalf("got %d name servers, want %d", len(upgraded.NameServers), len(wantHosts))
+	}
+
+	for i, nameServer := range upgraded.NameServers {
+		if nameServer.Host.ValueString() != wantHosts[i] {
+			t.Errorf("name server %d is %s, want %s", i, nameServer.Host, wantHosts[i])
+		}
+
+		if nameServer.ID.ValueInt64() != int64(i+1) || !nameServer.IsUsed.ValueBool() || nameServer.ToDelete.ValueBool() {
+			t.Errorf("computed attributes of %s were not kept: %+v", nameServer.Host, nameServer)
+		}
+	}
+
+	if upgraded.NameServers[2].IP.ValueString() != "192.0.2.1" {
+		t.Errorf("ip of ns.example.com = %s, want 192.0.2.1", upgraded.NameServers[2].IP)
+	}
+
+	// Attributes left unset in version 0, or added after it, are null.
+	for name, isNull := range map[string]bool{
+		"preflight_check":       upgraded.PreflightCheck.IsNull(),
+		"wait_for_propagation":  upgraded.WaitForPropagation == nil,
+		"last_task":             upgraded.LastTask.IsNull(),
+		"rollback_on_failure":   upgraded.RollbackOnFailure.IsNull(),
+		"migration":             upgraded.Migration == nil,
+		"parity_check":          upgraded.ParityCheck == nil,
+		"allow_dnssec_mismatch": upgraded.AllowDNSSECMismatch.IsNull(),
+	} {
+		if !isNull {
+			t.Errorf("%s is not null after the upgrade", name)
+		}
+	}
+}
@@ -3,9 +3,14 @@ package provider
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -13,6 +18,8 @@ import (
 )
 
 var _ provider.Provider = &CDCOvhNSProvider{}
+var _ provider.ProviderWithFunctions = &CDCOvhNSProvider{}
+var _ provider.ProviderWithEphemeralResources = &CDCOvhNSProvider{}
 
 type CDCOvhNSProvider struct {
 	version string
@@ -23,6 +30,15 @@ type CDCOvhNSProviderModel struct {
 	ApplicationKey    types.String `tfsdk:"application_key"`
 	ApplicationSecret types.String `tfsdk:"application_secret"`
 	ConsumerKey       types.String `tfsdk:"consumer_key"`
+	DNSResolver       types.String `tfsdk:"dns_resolver"`
+	DNSPort           types.Int64  `tfsdk:"dns_port"`
+	DNSTimeout        types.String `tfsdk:"dns_timeout"`
+}
+
+// CDCOvhNSProviderData is passed to resources and data sources on Configure.
+type CDCOvhNSProviderData struct {
+	Client *api.APIClient
+	DNS    *dnsclient.Client
 }
 
 func (p *CDCOvhNSProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -56,6 +72,19 @@ func (p *CDCOvhNSProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Sensitive: true,
 				Optional:  true,
 			},
+			"dns_resolver": schema.StringAttribute{
+				MarkdownDescription: "Recursive DNS resolver (`host` or `host:port`) used to look up name server addresses " +
+					"for DNS checks. Defaults to the first resolver from `/etc/resolv.conf`.",
+				Optional: true,
+			},
+			"dns_port": schema.Int64Attribute{
+				MarkdownDescription: "Port used when querying name servers directly during DNS checks. Defaults to `53`.",
+				Optional:            true,
+			},
+			"dns_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout of a single DNS query, as a duration string (eg: \"5s\"). Defaults to `5s`.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -101,18 +130,74 @@ func (p *CDCOvhNSProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	dnsConfig := dnsclient.Config{
+		Resolver: data.DNSResolver.ValueString(),
+		Port:     int(data.DNSPort.ValueInt64()),
+	}
+
+	if data.DNSTimeout.ValueString() != "" {
+		timeout, err := time.ParseDuration(data.DNSTimeout.ValueString())
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("dns_timeout"),
+				"Invalid DNS timeout",
+				"Provide a positive duration, eg: \"5s\"",
+			)
+			return
+		}
+		dnsConfig.Timeout = timeout
+	}
+
+	dnsClient, err := dnsclient.NewClient(dnsConfig)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create DNS Client",
+			"DNS client seems to be misconfigured: "+err.Error(),
+		)
+		return
+	}
+
+	providerData := &CDCOvhNSProviderData{
+		Client: client,
+		DNS:    dnsClient,
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
 }
 
 func (p *CDCOvhNSProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCDCOvhNSResource,
+		NewCDCGlueRecordResource,
+		NewCDCDSRecordsResource,
+		NewCDCDomainTransferLockResource,
+		NewCDCDomainRenewalResource,
+		NewCDCDomainContactsResource,
+		NewCDCDomainWhoisObfuscationResource,
+	}
+}
+
+func (p *CDCOvhNSProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewCDCDomainAuthInfoEphemeralResource,
 	}
 }
 
 func (p *CDCOvhNSProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewCDCDSDataDataSource,
+		NewCDCDomainDataSource,
+		NewCDCDomainsDataSource,
+	}
+}
+
+func (p *CDCOvhNSProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewDSDataFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,14 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+)
+
+// waitForTask blocks until the OVH task is done, failed, or ctx is cancelled.
+func waitForTask(ctx context.Context, client *api.APIClient, serviceName api.DomainName, id int64) error {
+	taskErr := make(chan error)
+	go client.CheckOVHTask(ctx, taskErr, serviceName, id)
+	return <-taskErr
+}
@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/api"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/dnsclient"
+	"github.com/capybaradevcloud/terraform-provider-cdcovhns/internal/tldrules"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// parseServiceName returns service_name in the form used by the OVH API.
+func parseServiceName(serviceName HostnameValue) (api.DomainName, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	domain, err := api.ParseDomainName(serviceName.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("service_name"),
+			"Domain name is not valid",
+			err.Error(),
+		)
+	}
+
+	return domain, diags
+}
+
+// validateNameServers checks host names, glue ips and duplicates. Values that
+// are not known yet are skipped.
+func validateNameServers(serviceName HostnameValue, nameServers []CDCNameServersModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	hosts := make(map[string]bool)
+	for _, nameServer := range nameServers {
+		if nameServer.Host.IsUnknown() {
+			continue
+		}
+
+		nsPath := nameServerPath(nameServer)
+		host := nameServer.Host.ValueString()
+		if err := validateHostname(host); err != nil {
+			diags.AddAttributeError(
+				nsPath.AtName("host"),
+				"Host is not valid",
+				err.Error(),
+			)
+			continue
+		}
+
+		canonical := nameServer.Host.Canonical()
+		if hosts[canonical] {
+			diags.AddAttributeError(
+				nsPath.AtName("host"),
+				"Duplicate name server",
+				fmt.Sprintf("%s is configured more than once", host),
+			)
+			continue
+		}
+		hosts[canonical] = true
+
+		if nameServer.IP.IsUnknown() || serviceName.IsUnknown() || serviceName.IsNull() {
+			continue
+		}
+
+		ip := nameServer.IP.ValueString()
+		inBailiwick := isInBailiwick(canonical, serviceName.ValueString())
+
+		switch {
+		case ip == "" && inBailiwick:
+			diags.AddAttributeError(
+				nsPath.AtName("ip"),
+				"Missing glue IP",
+				fmt.Sprintf("%s is inside %s, provide its ip so a glue record can be published", host, serviceName.ValueString()),
+			)
+		case ip != "" && !inBailiwick:
+			diags.AddAttributeError(
+				nsPath.AtName("ip"),
+				"Unexpected glue IP",
+				fmt.Sprintf("%s is outside %s, an ip can only be set for glue records of hosts inside the domain", host, serviceName.ValueString()),
+			)
+		case ip != "":
+			if err := validateGlueIP(ip); err != nil {
+				diags.AddAttributeError(
+					nsPath.AtName("ip"),
+					"IP is not valid",
+					fmt.Sprintf("%s: %s", host, err.Error()),
+				)
+			} else if !isPublicIP(ip) {
+				diags.AddAttributeWarning(
+					nsPath.AtName("ip"),
+					"IP is not public",
+					fmt.Sprintf("%s: %s is not a public unicast address, resolvers outside your network cannot reach it", host, ip),
+				)
+			}
+		}
+	}
+
+	return diags
+}
+
+// validateTLDRules checks the name servers against the delegation rules of the
+// registry of serviceName. Values that are not known yet are skipped.
+func validateTLDRules(serviceName HostnameValue, nameServers []CDCNameServersModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if serviceName.IsUnknown() || serviceName.IsNull() {
+		return diags
+	}
+
+	rule := tldrules.Lookup(serviceName.Canonical())
+	registry := "." + rule.Suffix
+	if rule.Suffix == "" {
+		registry = "this registry"
+	}
+
+	if len(nameServers) < rule.MinNameServers || len(nameServers) > rule.MaxNameServers {
+		diags.AddAttributeError(
+			path.Root("name_servers"),
+			"Wrong number of name servers",
+			fmt.Sprintf("%s accepts between %d and %d name servers, got %d", registry, rule.MinNameServers, rule.MaxNameServers, len(nameServers)),
+		)
+	}
+
+	ips := make(map[string]string)
+	for _, nameServer := range nameServers {
+		if nameServer.IP.IsUnknown() || nameServer.IP.ValueString() == "" {
+			continue
+		}
+
+		host := nameServer.Host.ValueString()
+		ip := net.ParseIP(nameServer.IP.ValueString())
+		if ip == nil {
+			continue
+		}
+
+		if otherHost, ok := ips[ip.String()]; ok && rule.DistinctIPs {
+			diags.AddAttributeError(
+				path.Root("name_servers"),
+				"Duplicate glue IP",
+				fmt.Sprintf("%s requires distinct ips, %s of %s is already used by %s", registry, ip.String(), host, otherHost),
+			)
+		}
+		ips[ip.String()] = host
+	}
+
+	return diags
+}
+
+// validateGlueRecord checks the host and ips of a glue record. Values that are
+// not known yet are skipped.
+func validateGlueRecord(serviceName HostnameValue, host HostnameValue, ips []types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !host.IsUnknown() && !host.IsNull() {
+		if err := validateHostname(host.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("host"), "Host is not valid", err.Error())
+		} else if !serviceName.IsUnknown() && !serviceName.IsNull() && !isInBailiwick(host.Canonical(), serviceName.ValueString()) {
+			diags.AddAttributeError(
+				path.Root("host"),
+				"Host outside the domain",
+				fmt.Sprintf("%s is outside %s, glue records can only be created for hosts inside the domain", host.ValueString(), serviceName.ValueString()),
+			)
+		}
+	}
+
+	seen := []string{}
+	for _, value := range ips {
+		if value.IsUnknown() || value.IsNull() {
+			continue
+		}
+
+		ip := value.ValueString()
+		if err := validateGlueIP(ip); err != nil {
+			diags.AddAttributeError(path.Root("ips"), "IP is not valid", err.Error())
+			continue
+		}
+
+		if !isPublicIP(ip) {
+			diags.AddAttributeWarning(
+				path.Root("ips"),
+				"IP is not public",
+				fmt.Sprintf("%s is not a public unicast address, resolvers outside your network cannot reach it", ip),
+			)
+		}
+
+		for _, other := range seen {
+			if sameIP(ip, other) {
+				diags.AddAttributeError(
+					path.Root("ips"),
+					"Duplicate glue IP",
+					fmt.Sprintf("%s is configured more than once", ip),
+				)
+			}
+		}
+		seen = append(seen, ip)
+	}
+
+	return diags
+}
+
+// validateHostname checks host with the grammar of api.ParseHostname. A single
+// trailing dot is accepted.
+func validateHostname(host string) error {
+	_, err := api.ParseHostname(host)
+	return err
+}
+
+// validateGlueIP accepts IPv4 addresses in dotted notation and IPv6
+// addresses.
+func validateGlueIP(value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return fmt.Errorf("%q is not an IPv4 or IPv6 address", value)
+	}
+
+	if ip.To4() != nil && strings.Contains(value, ":") {
+		return fmt.Errorf("%q is an IPv4-mapped IPv6 address, use the IPv4 notation instead", value)
+	}
+
+	return nil
+}
+
+// isPublicIP reports whether value is a global unicast address outside the
+// private ranges. Other addresses are accepted for lab and split-horizon
+// setups, but public resolvers cannot reach them.
+func isPublicIP(value string) bool {
+	ip := net.ParseIP(value)
+
+	return ip != nil && ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
+
+// isInBailiwick reports whether host is the zone itself or a name below it.
+func isInBailiwick(host string, zone string) bool {
+	host = dnsclient.CanonicalHost(host)
+	zone = dnsclient.CanonicalHost(zone)
+
+	return host == zone || strings.HasSuffix(host, "."+zone)
+}
+
+// sameIP compares two ip notations, falling back to string equality.
+func sameIP(a string, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+
+	return ipA.Equal(ipB)
+}
@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateHostname(t *testing.T) {
+	testCases := map[string]struct {
+		host    string
+		wantErr bool
+	}{
+		"simple":                 {host: "ns1.example.com"},
+		"trailing dot":           {host: "ns1.example.com."},
+		"uppercase":              {host: "NS1.Example.COM"},
+		"hyphen inside label":    {host: "ns-1.example.com"},
+		"punycode":               {host: "ns1.xn--bcher-kva.example"},
+		"unicode":                {host: "ns1.bücher.example"},
+		"63 characters label":    {host: "a123456789012345678901234567890123456789012345678901234567890bc.example.com"},
+		"empty":                  {host: "", wantErr: true},
+		"only a dot":             {host: ".", wantErr: true},
+		"single label":           {host: "localhost", wantErr: true},
+		"two trailing dots":      {host: "ns1.example.com..", wantErr: true},
+		"empty label":            {host: "ns1..example.com", wantErr: true},
+		"leading hyphen":         {host: "-ns1.example.com", wantErr: true},
+		"trailing hyphen":        {host: "ns1-.example.com", wantErr: true},
+		"underscore":             {host: "ns_1.example.com", wantErr: true},
+		"space":                  {host: "ns 1.example.com", wantErr: true},
+		"64 characters label":    {host: "a1234567890123456789012345678901234567890123456789012345678901bc.example.com", wantErr: true},
+		"longer than 253 chars":  {host: longHostname(254), wantErr: true},
+		"exactly 253 characters": {host: longHostname(253)},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateHostname(testCase.host)
+
+			if testCase.wantErr && err == nil {
+				t.Errorf("validateHostname(%q) returned no error", testCase.host)
+			}
+			if !testCase.wantErr && err != nil {
+				t.Errorf("validateHostname(%q) returned %q", testCase.host, err)
+			}
+		})
+	}
+}
+
+func TestValidateGlueIP(t *testing.T) {
+	testCases := map[string]struct {
+		ip         string
+		wantErr    bool
+		wantPublic bool
+	}{
+		"public IPv4":          {ip: "8.8.8.8", wantPublic: true},
+		"public IPv6":          {ip: "2001:4860:4860::8888", wantPublic: true},
+		"private IPv4":         {ip: "192.168.1.10"},
+		"private IPv6":         {ip: "fd00::1"},
+		"loopback IPv4":        {ip: "127.0.0.1"},
+		"loopback IPv6":        {ip: "::1"},
+		"link-local IPv6":      {ip: "fe80::1"},
+		"unspecified":          {ip: "0.0.0.0"},
+		"multicast":            {ip: "224.0.0.1"},
+		"IPv4-mapped IPv6":     {ip: "::ffff:8.8.8.8", wantErr: true},
+		"not an address":       {ip: "ns1.example.com", wantErr: true},
+		"out of range":         {ip: "256.1.1.1", wantErr: true},
+		"IPv4 with CIDR":       {ip: "8.8.8.0/24", wantErr: true},
+		"empty":                {ip: "", wantErr: true},
+		"IPv6 with zone index": {ip: "fe80::1%eth0", wantErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateGlueIP(testCase.ip)
+
+			if testCase.wantErr && err == nil {
+				t.Errorf("validateGlueIP(%q) returned no error", testCase.ip)
+			}
+			if !testCase.wantErr && err != nil {
+				t.Errorf("validateGlueIP(%q) returned %q", testCase.ip, err)
+			}
+
+			if testCase.wantErr {
+				return
+			}
+
+			if got := isPublicIP(testCase.ip); got != testCase.wantPublic {
+				t.Errorf("isPublicIP(%q) = %t, want %t", testCase.ip, got, testCase.wantPublic)
+			}
+		})
+	}
+}
+
+func TestIsInBailiwick(t *testing.T) {
+	testCases := map[string]struct {
+		host string
+		zone string
+		want bool
+	}{
+		"below the zone":         {host: "ns1.example.com", zone: "example.com", want: true},
+		"deep below the zone":    {host: "a.b.ns1.example.com", zone: "example.com", want: true},
+		"zone itself":            {host: "example.com", zone: "example.com", want: true},
+		"other notation":         {host: "NS1.Example.COM.", zone: "example.com", want: true},
+		"unicode zone":           {host: "ns1.xn--bcher-kva.example", zone: "bücher.example", want: true},
+		"unicode host":           {host: "ns1.bücher.example", zone: "xn--bcher-kva.example", want: true},
+		"other domain":           {host: "ns1.example.net", zone: "example.com", want: false},
+		"zone as label suffix":   {host: "ns1.notexample.com", zone: "example.com", want: false},
+		"parent of the zone":     {host: "com", zone: "example.com", want: false},
+		"sibling of the zone":    {host: "ns1.other.example.com", zone: "sub.example.com", want: false},
+		"zone in the middle":     {host: "example.com.attacker.net", zone: "example.com", want: false},
+		"zone with trailing dot": {host: "ns1.example.com", zone: "example.com.", want: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := isInBailiwick(testCase.host, testCase.zone); got != testCase.want {
+				t.Errorf("isInBailiwick(%q, %q) = %t, want %t", testCase.host, testCase.zone, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSameIP(t *testing.T) {
+	testCases := map[string]struct {
+		a    string
+		b    string
+		want bool
+	}{
+		"same IPv4":                {a: "192.0.2.1", b: "192.0.2.1", want: true},
+		"different IPv4":           {a: "192.0.2.1", b: "192.0.2.2", want: false},
+		"expanded IPv6":            {a: "2001:db8::1", b: "2001:0db8:0000:0000:0000:0000:0000:0001", want: true},
+		"uppercase IPv6":           {a: "2001:db8::a", b: "2001:DB8::A", want: true},
+		"IPv4 and mapped IPv6":     {a: "192.0.2.1", b: "::ffff:192.0.2.1", want: true},
+		"IPv4 and IPv6":            {a: "192.0.2.1", b: "2001:db8::1", want: false},
+		"empty":                    {a: "", b: "", want: true},
+		"empty and address":        {a: "", b: "192.0.2.1", want: false},
+		"invalid compared as text": {a: "not-an-ip", b: "not-an-ip", want: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := sameIP(testCase.a, testCase.b); got != testCase.want {
+				t.Errorf("sameIP(%q, %q) = %t, want %t", testCase.a, testCase.b, got, testCase.want)
+			}
+		})
+	}
+}
+
+// longHostname returns a host name of length characters made of labels of at
+// most 63 characters.
+func longHostname(length int) string {
+	labels := []string{}
+	for length > 64 {
+		labels = append(labels, strings.Repeat("a", 63))
+		length -= 64
+	}
+	labels = append(labels, strings.Repeat("b", length))
+
+	return strings.Join(labels, ".")
+}
+
+func TestValidateTLDRules(t *testing.T) {
+	testCases := map[string]struct {
+		serviceName   HostnameValue
+		nameServers   []CDCNameServersModel
+		wantSummaries []string
+	}{
+		"default rule": {
+			serviceName: NewHostnameValue("example.com"),
+			nameServers: []CDCNameServersModel{nameServer("ns1.example.net", ""), nameServer("ns2.example.net", "")},
+		},
+		"too few name servers": {
+			serviceName:   NewHostnameValue("example.com"),
+			nameServers:   []CDCNameServersModel{nameServer("ns1.example.net", "")},
+			wantSummaries: []string{"Wrong number of name servers"},
+		},
+		"too many name servers for de": {
+			serviceName: NewHostnameValue("example.de"),
+			nameServers: []CDCNameServersModel{
+				nameServer("ns1.example.net", ""), nameServer("ns2.example.net", ""), nameServer("ns3.example.net", ""),
+				nameServer("ns4.example.net", ""), nameServer("ns5.example.net", ""), nameServer("ns6.example.net", ""),
+			},
+			wantSummaries: []string{"Wrong number of name servers"},
+		},
+		"distinct ips for de": {
+			serviceName:   NewHostnameValue("example.de"),
+			nameServers:   []CDCNameServersModel{nameServer("ns1.example.de", "192.0.2.1"), nameServer("ns2.example.de", "192.0.2.1")},
+			wantSummaries: []string{"Duplicate glue IP"},
+		},
+		"distinct ips in other notation for it": {
+			serviceName:   NewHostnameValue("example.it"),
+			nameServers:   []CDCNameServersModel{nameServer("ns1.example.it", "2001:db8::1"), nameServer("ns2.example.it", "2001:DB8:0::1")},
+			wantSummaries: []string{"Duplicate glue IP"},
+		},
+		"shared ip allowed by default": {
+			serviceName: NewHostnameValue("example.com"),
+			nameServers: []CDCNameServersModel{nameServer("ns1.example.com", "192.0.2.1"), nameServer("ns2.example.com", "192.0.2.1")},
+		},
+		"count and distinct ips together": {
+			serviceName: NewHostnameValue("Example.DE."),
+			nameServers: []CDCNameServersModel{
+				nameServer("ns1.example.de", "192.0.2.1"), nameServer("ns2.example.de", "192.0.2.1"), nameServer("ns3.example.de", "192.0.2.3"),
+				nameServer("ns4.example.de", "192.0.2.4"), nameServer("ns5.example.de", "192.0.2.5"), nameServer("ns6.example.de", "192.0.2.6"),
+			},
+			wantSummaries: []string{"Wrong number of name servers", "Duplicate glue IP"},
+		},
+		"unknown ip skipped": {
+			serviceName: NewHostnameValue("example.de"),
+			nameServers: []CDCNameServersModel{
+				nameServer("ns1.example.de", "192.0.2.1"),
+				{Host: NewHostnameValue("ns2.example.de"), IP: types.StringUnknown()},
+			},
+		},
+		"unknown service name skipped": {
+			serviceName: NewHostnameUnknown(),
+			nameServers: []CDCNameServersModel{nameServer("ns1.example.net", "")},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := validateTLDRules(testCase.serviceName, testCase.nameServers)
+
+			var summaries []string
+			for _, diagnostic := range diags {
+				summaries = append(summaries, diagnostic.Summary())
+
+				withPath, ok := diagnostic.(diag.DiagnosticWithPath)
+				if !ok || !withPath.Path().Equal(path.Root("name_servers")) {
+					t.Errorf("diagnostic %q is not reported on name_servers", diagnostic.Summary())
+				}
+			}
+
+			if strings.Join(summaries, ", ") != strings.Join(testCase.wantSummaries, ", ") {
+				t.Errorf("got diagnostics [%s], want [%s]", strings.Join(summaries, ", "), strings.Join(testCase.wantSummaries, ", "))
+			}
+		})
+	}
+}
+
+func TestValidateNameServersPaths(t *testing.T) {
+	invalidHost := nameServer("ns_1.example.net", "")
+	missingIP := nameServer("ns1.example.com", "")
+	unexpectedIP := nameServer("ns1.example.net", "192.0.2.1")
+	validNameServer := nameServer("ns2.example.net", "")
+
+	diags := validateNameServers(NewHostnameValue("example.com"), []CDCNameServersModel{invalidHost, missingIP, unexpectedIP, validNameServer})
+
+	want := map[string]path.Path{
+		"Host is not valid":  nameServerPath(invalidHost).AtName("host"),
+		"Missing glue IP":    nameServerPath(missingIP).AtName("ip"),
+		"Unexpected glue IP": nameServerPath(unexpectedIP).AtName("ip"),
+	}
+
+	if len(diags) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %v", len(diags), len(want), diags)
+	}
+
+	for _, diagnostic := range diags {
+		withPath, ok := diagnostic.(diag.DiagnosticWithPath)
+		if !ok {
+			t.Errorf("diagnostic %q has no path", diagnostic.Summary())
+			continue
+		}
+
+		if wantPath, ok := want[diagnostic.Summary()]; !ok || !withPath.Path().Equal(wantPath) {
+			t.Errorf("diagnostic %q is reported on %s, want %s", diagnostic.Summary(), withPath.Path(), wantPath)
+		}
+	}
+}
+
+func nameServer(host string, ip string) CDCNameServersModel {
+	return CDCNameServersModel{
+		Host: NewHostnameValue(host),
+		IP:   types.StringValue(ip),
+	}
+}
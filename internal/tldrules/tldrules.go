@@ -0,0 +1,80 @@
+// Package tldrules holds the registry delegation rules per TLD. The rules live
+// in rules.json, next to this file; only the fields that differ from the
+// default entry need to be set for a TLD.
+package tldrules
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed rules.json
+var rulesJSON []byte
+
+var rules = mustParse(rulesJSON)
+
+type Rule struct {
+	// Suffix is the TLD the rule was found for, empty for the default rule.
+	Suffix string `json:"-"`
+
+	MinNameServers int  `json:"min_name_servers"`
+	MaxNameServers int  `json:"max_name_servers"`
+	DistinctIPs    bool `json:"distinct_ips"`
+}
+
+type table struct {
+	Default Rule
+	TLDs    map[string]Rule
+}
+
+// Lookup returns the rule of the longest matching suffix of domain, or the
+// default rule.
+func Lookup(domain string) Rule {
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(domain), "."), ".")
+
+	for i := 1; i < len(labels); i++ {
+		if rule, ok := rules.TLDs[strings.Join(labels[i:], ".")]; ok {
+			return rule
+		}
+	}
+
+	return rules.Default
+}
+
+func mustParse(data []byte) table {
+	parsed, err := parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// parse decodes every TLD entry on top of a copy of the default rule.
+func parse(data []byte) (table, error) {
+	var raw struct {
+		Default Rule                       `json:"default"`
+		TLDs    map[string]json.RawMessage `json:"tlds"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return table{}, fmt.Errorf("decoding TLD rules: %w", err)
+	}
+
+	parsed := table{
+		Default: raw.Default,
+		TLDs:    make(map[string]Rule, len(raw.TLDs)),
+	}
+
+	for suffix, override := range raw.TLDs {
+		rule := raw.Default
+		if err := json.Unmarshal(override, &rule); err != nil {
+			return table{}, fmt.Errorf("decoding TLD rules for %s: %w", suffix, err)
+		}
+		rule.Suffix = suffix
+		parsed.TLDs[suffix] = rule
+	}
+
+	return parsed, nil
+}
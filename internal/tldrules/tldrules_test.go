@@ -0,0 +1,149 @@
+package tldrules
+
+import (
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	testCases := map[string]struct {
+		domain string
+		want   Rule
+	}{
+		"de override": {
+			domain: "example.de",
+			want:   Rule{Suffix: "de", MinNameServers: 2, MaxNameServers: 5, DistinctIPs: true},
+		},
+		"it override": {
+			domain: "example.it",
+			want:   Rule{Suffix: "it", MinNameServers: 2, MaxNameServers: 6, DistinctIPs: true},
+		},
+		"max only override": {
+			domain: "example.fr",
+			want:   Rule{Suffix: "fr", MinNameServers: 2, MaxNameServers: 8},
+		},
+		"longest suffix": {
+			domain: "example.co.uk",
+			want:   Rule{Suffix: "co.uk", MinNameServers: 2, MaxNameServers: 10},
+		},
+		"second level under known TLD": {
+			domain: "example.org.uk",
+			want:   Rule{Suffix: "uk", MinNameServers: 2, MaxNameServers: 10},
+		},
+		"subdomain": {
+			domain: "sub.example.de",
+			want:   Rule{Suffix: "de", MinNameServers: 2, MaxNameServers: 5, DistinctIPs: true},
+		},
+		"uppercase and trailing dot": {
+			domain: "EXAMPLE.DE.",
+			want:   Rule{Suffix: "de", MinNameServers: 2, MaxNameServers: 5, DistinctIPs: true},
+		},
+		"fallback TLD": {
+			domain: "example.com",
+			want:   Rule{MinNameServers: 2, MaxNameServers: 13},
+		},
+		"TLD only": {
+			domain: "de",
+			want:   Rule{MinNameServers: 2, MaxNameServers: 13},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := Lookup(testCase.domain); got != testCase.want {
+				t.Errorf("Lookup(%q) = %+v, want %+v", testCase.domain, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := map[string]struct {
+		data    string
+		want    table
+		wantErr bool
+	}{
+		"override on top of default": {
+			data: `{
+				"default": {"min_name_servers": 2, "max_name_servers": 13, "distinct_ips": false},
+				"tlds": {"de": {"max_name_servers": 5, "distinct_ips": true}}
+			}`,
+			want: table{
+				Default: Rule{MinNameServers: 2, MaxNameServers: 13},
+				TLDs: map[string]Rule{
+					"de": {Suffix: "de", MinNameServers: 2, MaxNameServers: 5, DistinctIPs: true},
+				},
+			},
+		},
+		"override back to false": {
+			data: `{
+				"default": {"min_name_servers": 2, "max_name_servers": 13, "distinct_ips": true},
+				"tlds": {"fr": {"distinct_ips": false}}
+			}`,
+			want: table{
+				Default: Rule{MinNameServers: 2, MaxNameServers: 13, DistinctIPs: true},
+				TLDs: map[string]Rule{
+					"fr": {Suffix: "fr", MinNameServers: 2, MaxNameServers: 13},
+				},
+			},
+		},
+		"no TLDs": {
+			data: `{"default": {"min_name_servers": 1, "max_name_servers": 4}}`,
+			want: table{
+				Default: Rule{MinNameServers: 1, MaxNameServers: 4},
+				TLDs:    map[string]Rule{},
+			},
+		},
+		"invalid JSON": {
+			data:    `{"default": `,
+			wantErr: true,
+		},
+		"invalid override": {
+			data:    `{"default": {}, "tlds": {"de": {"max_name_servers": "five"}}}`,
+			wantErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parse([]byte(testCase.data))
+
+			if testCase.wantErr {
+				if err == nil {
+					t.Errorf("parse returned no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parse returned %q", err)
+			}
+
+			if got.Default != testCase.want.Default {
+				t.Errorf("default rule = %+v, want %+v", got.Default, testCase.want.Default)
+			}
+
+			if len(got.TLDs) != len(testCase.want.TLDs) {
+				t.Errorf("got %d TLD rules, want %d", len(got.TLDs), len(testCase.want.TLDs))
+			}
+
+			for suffix, want := range testCase.want.TLDs {
+				if got.TLDs[suffix] != want {
+					t.Errorf("rule of %s = %+v, want %+v", suffix, got.TLDs[suffix], want)
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedRules(t *testing.T) {
+	parsed, err := parse(rulesJSON)
+	if err != nil {
+		t.Fatalf("rules.json is not valid: %s", err)
+	}
+
+	for suffix, rule := range parsed.TLDs {
+		if rule.MinNameServers < 1 || rule.MinNameServers > rule.MaxNameServers {
+			t.Errorf("rule of %s accepts between %d and %d name servers", suffix, rule.MinNameServers, rule.MaxNameServers)
+		}
+	}
+}